@@ -4,13 +4,16 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"fmt"
+	"time"
+
 	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
 	"github.com/spf13/cobra"
 )
 
 // addCmd represents the add command
 var addCmd = &cobra.Command{
-	Use:   "add",
+	Use:   "add <description>",
 	Short: "Add a new task to your TODO List",
 	Long: `Add a new task to your TODO List. 
 For example:
@@ -18,19 +21,71 @@ task add "Learn Go"
 
 This will add a new task "Learn Go" to your TODO List.
 Need "quotes" around the task description if you want space.
+
+Give a deadline with --due, as a date or in plain words:
+task add "Ship release" --due "next friday"
+task add "Renew passport" --due 2026-11-01
+
+Words starting with + become tags and a word starting with @ sets the
+project, or use the flags:
+task add "Fix login redirect +bug @web" --priority high
+task add "Fix login redirect" --tag bug --project web -p h
+
+Repeat a task with --every; completing it adds the next occurrence:
+task add "Water plants" --every "mon,thu"
+task add "Pay rent" --due 2026-11-01 --every month
+
+Make it a subtask with --parent, or wait for other tasks with --blocked-by:
+task add "Book venue" --parent 4 --blocked-by 5,6
 	`,
-	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) == 0 {
-			cmd.Help()
-			return
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		newTask := tasks.Tasks{Description: args[0]}
+
+		if due, _ := cmd.Flags().GetString("due"); due != "" {
+			var err error
+			newTask.Due, err = tasks.ParseDue(due, time.Now())
+			if err != nil {
+				return err
+			}
+		}
+
+		priority, _ := cmd.Flags().GetString("priority")
+		var err error
+		newTask.Priority, err = tasks.ParsePriority(priority)
+		if err != nil {
+			return err
+		}
+		newTask.Tags, _ = cmd.Flags().GetStringSlice("tag")
+		newTask.Project, _ = cmd.Flags().GetString("project")
+		newTask.Recur, _ = cmd.Flags().GetString("every")
+		if parent, _ := cmd.Flags().GetString("parent"); parent != "" {
+			if newTask.Parent, err = parseID(parent); err != nil {
+				return err
+			}
+		}
+		blockers, _ := cmd.Flags().GetStringSlice("blocked-by")
+		if newTask.BlockedBy, err = parseIDs(blockers); err != nil {
+			return err
+		}
+
+		newTask, err = tasks.AddNewTask(store, newTask)
+		if err != nil {
+			return err
 		}
-		description := args[0]
-		tasks.AddNewTask(description)
+		return printer.PrintTask(newTask, fmt.Sprintf("Task %d added successfully", newTask.ID))
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(addCmd)
+	addCmd.Flags().String("due", "", `due date, e.g. "2026-11-01", "tomorrow", "next friday" or "3d"`)
+	addCmd.Flags().StringP("priority", "p", "", "priority: high, medium or low")
+	addCmd.Flags().StringSlice("tag", nil, "tag to attach, may be repeated")
+	addCmd.Flags().String("project", "", "project the task belongs to")
+	addCmd.Flags().String("every", "", `repeat the task: day, week, month, year, "2w", "mon,thu" or a cron spec such as "0 9 * * 1-5"`)
+	addCmd.Flags().String("parent", "", "ID of the task this is a subtask of")
+	addCmd.Flags().StringSlice("blocked-by", nil, "IDs of tasks that must be done first, may be repeated")
 
 	// Here you will define your flags and configuration settings.
 
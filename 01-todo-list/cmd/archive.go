@@ -0,0 +1,96 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/spf13/cobra"
+)
+
+// archiveCmd represents the archive command
+var archiveCmd = &cobra.Command{
+	Use:   "archive [id...]",
+	Short: "Move completed tasks into the archive",
+	Long: `Move completed tasks out of the live list into an archive kept beside
+	the data file, so everyday commands stay fast. Without arguments every
+	completed task is archived.
+	For example:
+	tasks archive
+	tasks archive 3 5 --dry-run
+	tasks archive --older-than 30d
+
+	Archive tasks automatically once they have been done for a while, or
+	turn that off again:
+	tasks archive --auto-after 14d
+	tasks archive --auto-after off
+
+	Archived tasks are shown with tasks list --archived and brought back
+	with tasks restore.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		archive, err := openArchive()
+		if err != nil {
+			return err
+		}
+		defer archive.Close()
+
+		// Archiving bypasses the journal, tasks restore is its undo
+		live := journal.Unwrap()
+
+		if cmd.Flags().Changed("auto-after") {
+			value, _ := cmd.Flags().GetString("auto-after")
+			var after time.Duration
+			if value != "off" {
+				if after, err = tasks.ParseDuration(value); err != nil {
+					return err
+				}
+			}
+			if err := tasks.SetAutoArchiveAfter(dataPath, after); err != nil {
+				return err
+			}
+			if after == 0 {
+				return printer.PrintTasks(nil, "Automatic archiving turned off")
+			}
+			archived, err := tasks.AutoArchive(live, archive, after)
+			if err != nil {
+				return err
+			}
+			return printer.PrintTasks(archived, fmt.Sprintf("Tasks completed more than %s ago are archived automatically, %s archived now", value, countTasks(len(archived))))
+		}
+
+		opts := tasks.ListOptions{Status: tasks.StatusDone}
+		if len(args) > 0 || filtered(cmd) {
+			if opts, err = selectionFromFlags(cmd, args, tasks.StatusDone); err != nil {
+				return err
+			}
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		archived, err := tasks.ArchiveTasks(live, archive, opts, dryRun)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			return printer.PrintTasks(archived, fmt.Sprintf("Would archive %s", countTasks(len(archived))))
+		}
+		return printer.PrintTasks(archived, fmt.Sprintf("%s archived", countTasks(len(archived))))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(archiveCmd)
+	addSelectionFlags(archiveCmd)
+	archiveCmd.Flags().String("auto-after", "", `archive tasks automatically once completed this long, e.g. 14d, or "off"`)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// archiveCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// archiveCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
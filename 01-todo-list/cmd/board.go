@@ -0,0 +1,84 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+)
+
+// boardCmd represents the board command
+var boardCmd = &cobra.Command{
+	Use:   "board [query]",
+	Short: "Show tasks as a board with a column per workflow state",
+	Long: `Show tasks side by side in a column for each workflow state, most
+	urgent first. The done column only holds tasks completed in the last
+	week unless --done-within says otherwise.
+	For example:
+	tasks board
+	tasks board project:web
+	tasks board --done-within 30d
+
+	A query narrows down the tasks as in tasks list.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workflow, err := tasks.LoadWorkflow(dataPath)
+		if err != nil {
+			return err
+		}
+		opts := tasks.ListOptions{
+			Status: tasks.StatusAll,
+			Query:  strings.Join(args, " "),
+		}
+		var doneWithin time.Duration
+		if value, _ := cmd.Flags().GetString("done-within"); value != "all" {
+			if doneWithin, err = tasks.ParseDuration(value); err != nil {
+				return err
+			}
+		}
+
+		board, err := tasks.BoardTasks(store, workflow, opts, doneWithin)
+		if err != nil {
+			return err
+		}
+		width, _ := cmd.Flags().GetInt("width")
+		if width == 0 {
+			width = terminalWidth()
+		}
+		return printer.PrintBoard(board, width)
+	},
+}
+
+// terminalWidth is the width of the terminal on standard output, or of
+// $COLUMNS, falling back to 100 columns.
+func terminalWidth() int {
+	if width, _, err := term.GetSize(os.Stdout.Fd()); err == nil && width > 0 {
+		return width
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return 100
+}
+
+func init() {
+	rootCmd.AddCommand(boardCmd)
+	boardCmd.Flags().String("done-within", "7d", "show tasks completed within this long ago, or all")
+	boardCmd.Flags().Int("width", 0, "width of the board, the terminal's by default")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// boardCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// boardCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/spf13/cobra"
+)
+
+// bulkAction is one of the bulk functions of the tasks package.
+type bulkAction func(s tasks.Store, opts tasks.ListOptions, dryRun bool) ([]tasks.Tasks, error)
+
+// addSelectionFlags adds the filters shared by complete, uncomplete and
+// delete for acting on many tasks at once.
+func addSelectionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("tag", nil, "only tasks carrying this tag, may be repeated")
+	cmd.Flags().String("project", "", "only tasks in this project")
+	cmd.Flags().String("priority", "", "only tasks with this priority")
+	cmd.Flags().Bool("overdue", false, "only open tasks past their due date")
+	cmd.Flags().String("older-than", "", "only tasks completed, or if open created, longer ago than a duration, e.g. 30d")
+	cmd.Flags().String("where", "", `only tasks matching a list query, e.g. "due<0d -tag:keep"`)
+	cmd.Flags().Bool("dry-run", false, "show the tasks that would change without changing them")
+}
+
+// selectionFromFlags builds the options picking the tasks a bulk command
+// acts on. Filters apply to tasks with the given status; tasks named by ID
+// are taken whatever their status. Without IDs at least one filter is
+// needed, so a bare command never touches every task.
+func selectionFromFlags(cmd *cobra.Command, args []string, status string) (tasks.ListOptions, error) {
+	flags := cmd.Flags()
+	opts := tasks.ListOptions{Status: status}
+
+	var err error
+	if opts.IDs, err = parseIDs(args); err != nil {
+		return opts, err
+	}
+	if len(opts.IDs) > 0 {
+		opts.Status = tasks.StatusAll
+	}
+
+	opts.Tags, _ = flags.GetStringSlice("tag")
+	opts.Project, _ = flags.GetString("project")
+	opts.Overdue, _ = flags.GetBool("overdue")
+	opts.Query, _ = flags.GetString("where")
+	priority, _ := flags.GetString("priority")
+	if opts.Priority, err = tasks.ParsePriority(priority); err != nil {
+		return opts, err
+	}
+	if older, _ := flags.GetString("older-than"); older != "" {
+		if opts.OlderThan, err = tasks.ParseDuration(older); err != nil {
+			return opts, err
+		}
+	}
+
+	if len(opts.IDs) == 0 && !filtered(cmd) {
+		return opts, errors.New("give task IDs or a filter such as --tag or --older-than")
+	}
+	return opts, nil
+}
+
+// filtered reports whether any selection filter was given.
+func filtered(cmd *cobra.Command) bool {
+	for _, name := range []string{"tag", "project", "priority", "overdue", "older-than", "where", "completed"} {
+		if flag := cmd.Flags().Lookup(name); flag != nil && flag.Changed {
+			return true
+		}
+	}
+	return false
+}
+
+// runBulk applies action to the selected tasks and reports the result,
+// naming the single task acted on as the one-ID commands always have.
+func runBulk(cmd *cobra.Command, opts tasks.ListOptions, action bulkAction, verb, done string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	changed, err := action(store, opts, dryRun)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case dryRun:
+		return printer.PrintTasks(changed, fmt.Sprintf("Would %s %s", verb, countTasks(len(changed))))
+	case len(changed) == 0:
+		return printer.PrintTasks(changed, "No tasks matched")
+	case len(opts.IDs) == 1:
+		return printer.PrintTask(changed[0], fmt.Sprintf("Task %d %s", changed[0].ID, done))
+	}
+	return printer.PrintTasks(changed, fmt.Sprintf("%s %s", countTasks(len(changed)), done))
+}
+
+func countTasks(n int) string {
+	if n == 1 {
+		return "1 task"
+	}
+	return fmt.Sprintf("%d tasks", n)
+}
@@ -5,7 +5,6 @@ package cmd
 
 import (
 	"fmt"
-	"strconv"
 
 	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
 	"github.com/spf13/cobra"
@@ -13,32 +12,47 @@ import (
 
 // completeCmd represents the complete command
 var completeCmd = &cobra.Command{
-	Use:   "complete",
+	Use:   "complete [id...]",
 	Short: "Mark a task as complete",
 	Long: `Mark a task as complete by providing the task ID.
 	For example:
 	tasks complete 1
 	
-	This will mark the task with ID 1 as complete.`,
+	This will mark the task with ID 1 as complete.
 
-	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) < 1 {
-			fmt.Fprint(cmd.OutOrStderr(), "You need to provide the task ID to complete")
-			return
-		}
+	Several tasks can be given by ID or range, or picked with filters:
+	tasks complete 3 5 7-10
+	tasks complete --tag errands --dry-run
+
+	A task with open subtasks can only be completed along with them, or
+	with --force.`,
 
-		taskId, err := strconv.Atoi(args[0])
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := selectionFromFlags(cmd, args, tasks.StatusOpen)
 		if err != nil {
-			fmt.Fprintln(cmd.OutOrStderr(), "Invalid task ID")
-			return
+			return err
 		}
-
-		tasks.CompleteTask(taskId)
+		force, _ := cmd.Flags().GetBool("force")
+		var next []tasks.Tasks
+		complete := func(s tasks.Store, opts tasks.ListOptions, dryRun bool) ([]tasks.Tasks, error) {
+			var done []tasks.Tasks
+			done, next, err = tasks.CompleteTasks(s, opts, dryRun, force)
+			return done, err
+		}
+		if err := runBulk(cmd, opts, complete, "complete", "completed"); err != nil {
+			return err
+		}
+		for _, task := range next {
+			printer.PrintNote(fmt.Sprintf("Next occurrence added as task %d, due %s", task.ID, task.Due.Format("Mon 2006-01-02 15:04")))
+		}
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(completeCmd)
+	addSelectionFlags(completeCmd)
+	completeCmd.Flags().Bool("force", false, "Complete tasks even if they have open subtasks")
 
 	// Here you will define your flags and configuration settings.
 
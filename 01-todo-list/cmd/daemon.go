@@ -0,0 +1,124 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/spf13/cobra"
+)
+
+// daemonCmd represents the daemon command
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Send reminders before tasks are due",
+	Long: `Keep watching the tasks and send a reminder when an open task gets
+	close to its due date, at each of the lead times given. Reminders go to
+	standard output, desktop notifications through notify-send, or a webhook
+	on this machine. Reminders sent are recorded beside the data file, so a
+	restarted daemon does not send them again.
+	For example:
+	tasks daemon
+	tasks daemon --lead 1d,1h,0 --notify stdout,notify-send
+	tasks daemon --notify webhook --webhook http://localhost:8080/reminders
+	tasks daemon --once
+
+	Tasks more than a day overdue get no more reminders.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		leadValues, _ := cmd.Flags().GetStringSlice("lead")
+		var leads []time.Duration
+		for _, value := range leadValues {
+			lead, err := tasks.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+			if lead < 0 {
+				return fmt.Errorf("lead time %q is negative", value)
+			}
+			leads = append(leads, lead)
+		}
+		if len(leads) == 0 {
+			return fmt.Errorf("give at least one lead time")
+		}
+
+		notifiers, err := notifiersFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		daemon := &tasks.Daemon{
+			Store:     store,
+			Notifiers: notifiers,
+			Leads:     leads,
+			LogPath:   tasks.RemindersPath(dataPath),
+			Errors:    cmd.ErrOrStderr(),
+		}
+
+		if once, _ := cmd.Flags().GetBool("once"); once {
+			_, err := daemon.Check(time.Now())
+			return err
+		}
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if interval <= 0 {
+			return fmt.Errorf("interval must be positive")
+		}
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return daemon.Run(ctx, interval)
+	},
+}
+
+// notifiersFromFlags builds the notifiers chosen with --notify, by name.
+func notifiersFromFlags(cmd *cobra.Command) (map[string]tasks.Notifier, error) {
+	names, _ := cmd.Flags().GetStringSlice("notify")
+	webhook, _ := cmd.Flags().GetString("webhook")
+	notifiers := make(map[string]tasks.Notifier)
+	for _, name := range names {
+		switch name {
+		case "stdout":
+			notifiers[name] = tasks.WriterNotifier{Out: cmd.OutOrStdout()}
+		case "notify-send":
+			notifiers[name] = tasks.NotifySend{}
+		case "webhook":
+			if webhook == "" {
+				return nil, fmt.Errorf("--notify webhook needs --webhook")
+			}
+			n, err := tasks.NewWebhookNotifier(webhook)
+			if err != nil {
+				return nil, err
+			}
+			notifiers[name] = n
+		default:
+			return nil, fmt.Errorf("unknown notifier %q (want stdout, notify-send or webhook)", name)
+		}
+	}
+	if len(notifiers) == 0 {
+		return nil, fmt.Errorf("give at least one notifier")
+	}
+	return notifiers, nil
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+	daemonCmd.Flags().StringSlice("lead", []string{"1h", "10m"}, "how long before the due date to remind, such as 1d,1h,0")
+	daemonCmd.Flags().StringSlice("notify", []string{"stdout"}, "where reminders go: stdout, notify-send and webhook")
+	daemonCmd.Flags().String("webhook", "", "URL on this machine to post reminders to as JSON")
+	daemonCmd.Flags().Duration("interval", 30*time.Second, "how often to check the tasks")
+	daemonCmd.Flags().Bool("once", false, "check once and exit, for running from cron")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// daemonCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// daemonCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
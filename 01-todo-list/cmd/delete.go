@@ -4,39 +4,41 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"fmt"
-	"strconv"
-
 	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
 	"github.com/spf13/cobra"
 )
 
 // deleteCmd represents the delete command
 var deleteCmd = &cobra.Command{
-	Use:   "delete",
+	Use:   "delete [id...]",
 	Short: "Delete a task from your TODO List",
 	Long: `Delete your task from your TODO List.
 	
 	For example:
 	task delete 1
 	
-	This will delete the task with ID 1 from your TODO List.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) == 0 {
-			cmd.Help()
-			return
+	This will delete the task with ID 1 from your TODO List.
+
+	Several tasks can be given by ID or range, or picked with filters:
+	task delete 3 5 7-10
+	task delete --completed --older-than 30d --dry-run`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		status := tasks.StatusAll
+		if completed, _ := cmd.Flags().GetBool("completed"); completed {
+			status = tasks.StatusDone
 		}
-		taskId, err := strconv.Atoi(args[0])
+		opts, err := selectionFromFlags(cmd, args, status)
 		if err != nil {
-			fmt.Fprintln(cmd.OutOrStderr(), "Invalid task ID")
-			return
+			return err
 		}
-		tasks.DeleteTask(taskId)
+		return runBulk(cmd, opts, tasks.DeleteTasks, "delete", "deleted")
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(deleteCmd)
+	addSelectionFlags(deleteCmd)
+	deleteCmd.Flags().BoolP("completed", "c", false, "only completed tasks")
 
 	// Here you will define your flags and configuration settings.
 
@@ -0,0 +1,60 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/spf13/cobra"
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the data file for corrupt rows",
+	Long: `Scan the CSV data file for missing headers, bad or duplicate IDs,
+	invalid dates and empty descriptions, reporting the line and field of each.
+	For example:
+	tasks doctor
+
+	With --fix a repaired file is written and the original kept as a backup:
+	tasks doctor --fix`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		csvStore, ok := journal.Unwrap().(*tasks.CSVStore)
+		if !ok {
+			return errors.New("doctor only checks csv data files")
+		}
+
+		fix, _ := cmd.Flags().GetBool("fix")
+		report, err := csvStore.Doctor(fix)
+		if err != nil {
+			return err
+		}
+		if err := printer.PrintDoctorReport(report); err != nil {
+			return err
+		}
+		if len(report.Problems) > 0 && report.Backup == "" {
+			return fmt.Errorf("found %d problems, run tasks doctor --fix to repair them", len(report.Problems))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().Bool("fix", false, "rewrite a repaired file, keeping a backup of the original")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// doctorCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// doctorCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -0,0 +1,194 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/spf13/cobra"
+)
+
+// editCmd represents the edit command
+var editCmd = &cobra.Command{
+	Use:   "edit <id>",
+	Short: "Change a task's description or details",
+	Long: `Change a task in place, keeping its ID and creation time.
+	For example:
+	tasks edit 3 --description "Tidy the whole office"
+	tasks edit 3 --due friday --priority high --tag home
+	tasks edit 3 --due none --untag home
+	tasks edit 3 --parent 1 --blocked-by 4 --unblock 2
+
+	Without any flags the task is opened in $VISUAL or $EDITOR:
+	tasks edit 3`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskId, err := parseID(args[0])
+		if err != nil {
+			return err
+		}
+
+		edit, err := editFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		if edit.Empty() {
+			var task tasks.Tasks
+			if task, edit, err = editInEditor(taskId); err != nil {
+				return err
+			}
+			if edit.Empty() {
+				return printer.PrintTask(task, fmt.Sprintf("Task %d unchanged", task.ID))
+			}
+		}
+
+		task, err := tasks.EditTask(store, taskId, edit)
+		if err != nil {
+			return err
+		}
+		return printer.PrintTask(task, fmt.Sprintf("Task %d updated", task.ID))
+	},
+}
+
+// editFromFlags collects the changes given on the command line.
+func editFromFlags(cmd *cobra.Command) (tasks.TaskEdit, error) {
+	var edit tasks.TaskEdit
+	flags := cmd.Flags()
+
+	if flags.Changed("description") {
+		description, _ := flags.GetString("description")
+		edit.Description = &description
+	}
+	if flags.Changed("due") {
+		var due time.Time
+		if value, _ := flags.GetString("due"); value != "none" {
+			var err error
+			if due, err = tasks.ParseDue(value, time.Now()); err != nil {
+				return edit, err
+			}
+		}
+		edit.Due = &due
+	}
+	if flags.Changed("priority") {
+		value, _ := flags.GetString("priority")
+		if value == "none" {
+			value = ""
+		}
+		priority, err := tasks.ParsePriority(value)
+		if err != nil {
+			return edit, err
+		}
+		edit.Priority = &priority
+	}
+	if flags.Changed("project") {
+		project, _ := flags.GetString("project")
+		edit.Project = &project
+	}
+	if flags.Changed("every") {
+		recur, _ := flags.GetString("every")
+		if recur == "none" {
+			recur = ""
+		}
+		edit.Recur = &recur
+	}
+	if flags.Changed("parent") {
+		parent := 0
+		if value, _ := flags.GetString("parent"); value != "none" {
+			var err error
+			if parent, err = parseID(value); err != nil {
+				return edit, err
+			}
+		}
+		edit.Parent = &parent
+	}
+	edit.AddTags, _ = flags.GetStringSlice("tag")
+	edit.RemoveTags, _ = flags.GetStringSlice("untag")
+	var err error
+	blockers, _ := flags.GetStringSlice("blocked-by")
+	if edit.AddBlockers, err = parseIDs(blockers); err != nil {
+		return edit, err
+	}
+	unblock, _ := flags.GetStringSlice("unblock")
+	if edit.RemoveBlockers, err = parseIDs(unblock); err != nil {
+		return edit, err
+	}
+	return edit, nil
+}
+
+// editInEditor opens the task in the user's editor and returns it as it
+// was along with what was changed.
+func editInEditor(id int) (tasks.Tasks, tasks.TaskEdit, error) {
+	task, err := store.Get(id)
+	if err != nil {
+		return task, tasks.TaskEdit{}, err
+	}
+
+	file, err := os.CreateTemp("", fmt.Sprintf("task-%d-*.txt", id))
+	if err != nil {
+		return task, tasks.TaskEdit{}, err
+	}
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(tasks.FormatEditable(task))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return task, tasks.TaskEdit{}, err
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	// $EDITOR may carry arguments, such as "code --wait"
+	words := strings.Fields(editor)
+	run := exec.Command(words[0], append(words[1:], file.Name())...)
+	run.Stdin, run.Stdout, run.Stderr = os.Stdin, os.Stderr, os.Stderr
+	if err := run.Run(); err != nil {
+		return task, tasks.TaskEdit{}, fmt.Errorf("editor %s failed: %w", words[0], err)
+	}
+
+	text, err := os.ReadFile(file.Name())
+	if err != nil {
+		return task, tasks.TaskEdit{}, err
+	}
+	if len(strings.TrimSpace(string(text))) == 0 {
+		return task, tasks.TaskEdit{}, errors.New("edit aborted, the file was left empty")
+	}
+	edit, err := tasks.ParseEditable(string(text), task, time.Now())
+	return task, edit, err
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+	editCmd.Flags().String("description", "", "new description")
+	editCmd.Flags().String("due", "", `new due date, or "none" to remove it`)
+	editCmd.Flags().StringP("priority", "p", "", `new priority: high, medium, low or "none"`)
+	editCmd.Flags().StringSlice("tag", nil, "tag to attach, may be repeated")
+	editCmd.Flags().StringSlice("untag", nil, "tag to remove, may be repeated")
+	editCmd.Flags().String("project", "", `new project, "" to clear it`)
+	editCmd.Flags().String("every", "", `new recurrence rule, or "none" to stop repeating`)
+	editCmd.Flags().String("parent", "", `ID of the new parent task, or "none" to move it to the top level`)
+	editCmd.Flags().StringSlice("blocked-by", nil, "ID of a task that must be done first, may be repeated")
+	editCmd.Flags().StringSlice("unblock", nil, "ID of a blocking task to remove, may be repeated")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// editCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// editCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
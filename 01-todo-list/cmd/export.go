@@ -0,0 +1,66 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"strings"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/spf13/cobra"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export [query]",
+	Short: "Write tasks out as JSON, CSV, todo.txt or Markdown",
+	Long: `Write every task, open and completed, to standard output in one of the
+	exchange formats. json and csv keep every field and read back with
+	tasks import; todo.txt suits other todo.txt tools, and markdown gives a
+	checklist to paste into notes or issues.
+	For example:
+	tasks export > backup.json
+	tasks export --format todo.txt > todo.txt
+	tasks export --format markdown "project:web status:open"
+
+	A query narrows down the tasks as in tasks list.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		opts := tasks.ListOptions{
+			Status: tasks.StatusAll,
+			Query:  strings.Join(args, " "),
+		}
+
+		from := store
+		if archived, _ := cmd.Flags().GetBool("archived"); archived {
+			archive, err := openArchive()
+			if err != nil {
+				return err
+			}
+			defer archive.Close()
+			from = archive
+		}
+
+		list, err := tasks.ListTasks(from, opts)
+		if err != nil {
+			return err
+		}
+		return tasks.ExportTasks(cmd.OutOrStdout(), list.All(), format)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().String("format", tasks.FormatJSON, "format to write: json, csv, todo.txt or markdown")
+	exportCmd.Flags().Bool("archived", false, "export archived tasks instead")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// exportCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// exportCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
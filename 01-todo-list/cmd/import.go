@@ -0,0 +1,89 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Add tasks from a JSON, CSV, todo.txt or Markdown file",
+	Long: `Add the tasks in a file to your TODO List. The format follows the file
+	name (.json, .csv, .txt or .md) unless given with --format; use - to
+	read standard input.
+	For example:
+	tasks import ~/todo.txt
+	tasks import backup.json --dry-run
+	tasks export --list work | tasks import - --format json
+
+	Imported tasks get new IDs, and their subtasks and blockers are linked
+	up again. Tasks with the same description and project as one already
+	in the list are skipped unless --allow-duplicates is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		if format == "" && args[0] != "-" {
+			var err error
+			if format, err = tasks.FormatFromPath(args[0]); err != nil {
+				return err
+			}
+		}
+		if format == "" {
+			format = tasks.FormatJSON
+		}
+
+		var in io.Reader = cmd.InOrStdin()
+		if args[0] != "-" {
+			file, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			in = file
+		}
+
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		allowDuplicates, _ := cmd.Flags().GetBool("allow-duplicates")
+		result, err := tasks.ImportTasks(store, in, format, dryRun, allowDuplicates)
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+
+		message := fmt.Sprintf("Imported %s", countTasks(len(result.Added)))
+		if dryRun {
+			message = fmt.Sprintf("Would import %s", countTasks(len(result.Added)))
+		}
+		if err := printer.PrintTasks(result.Added, message); err != nil {
+			return err
+		}
+		for _, task := range result.Duplicates {
+			printer.PrintNote(fmt.Sprintf("Skipped duplicate %q", task.Description))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().String("format", "", "format of the file: json, csv, todo.txt or markdown")
+	importCmd.Flags().Bool("dry-run", false, "show what would be imported without changing anything")
+	importCmd.Flags().Bool("allow-duplicates", false, "import tasks even if the list already has them")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// importCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// importCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -4,32 +4,105 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"strings"
+
 	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
 	"github.com/spf13/cobra"
 )
 
 // listCmd represents the list command
 var listCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List all tasks in your TODO List",
-	Long: `List all tasks in your TODO List. 
+	Use:   "list [query]",
+	Short: "List the uncompleted tasks in your TODO List",
+	Long: `List the uncompleted tasks in your TODO List.
 	For example:
 	tasks list
-	
-	This will list all tasks in your TODO List.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		all, _ := cmd.Flags().GetBool("all")
-		if all {
-			tasks.ShowAllTask()
-		} else {
-			tasks.ShowCompletedTasks()
+
+	Use -a to list all tasks, or -c for completed ones only. Tasks moved
+	away with tasks archive are listed with --archived.
+
+	Deadlines can be narrowed down and sorted:
+	tasks list --overdue
+	tasks list --due-within 3d --sort due
+
+	Or sliced by tag, project and priority:
+	tasks list --tag ops --project web --priority high
+	tasks list -a --group-by project
+
+	Subtasks are drawn under their parents with --tree:
+	tasks list --tree
+
+	Anything else can be written as a query of space separated terms:
+	tasks list "status:open due<7d tag:ops sort:-priority"
+	tasks list "status:done completed>-7d -project:web group:tag"
+
+	Fields are status, state, tag, project, priority, id, desc, due,
+	created, completed, sort and group. Compare with : = < > <= >=, prefix a term
+	with - to negate it, and use plain words to match the description.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := tasks.ListOptions{
+			Status: tasks.StatusOpen,
+			Query:  strings.Join(args, " "),
+		}
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			opts.Status = tasks.StatusAll
+		}
+		if completed, _ := cmd.Flags().GetBool("completed"); completed {
+			opts.Status = tasks.StatusDone
+		}
+		opts.Overdue, _ = cmd.Flags().GetBool("overdue")
+		opts.SortBy, _ = cmd.Flags().GetString("sort")
+		opts.GroupBy, _ = cmd.Flags().GetString("group-by")
+		opts.Tree, _ = cmd.Flags().GetBool("tree")
+		opts.Tags, _ = cmd.Flags().GetStringSlice("tag")
+		opts.Project, _ = cmd.Flags().GetString("project")
+		priority, _ := cmd.Flags().GetString("priority")
+		var err error
+		opts.Priority, err = tasks.ParsePriority(priority)
+		if err != nil {
+			return err
+		}
+		if within, _ := cmd.Flags().GetString("due-within"); within != "" {
+			opts.DueWithin, err = tasks.ParseDuration(within)
+			if err != nil {
+				return err
+			}
+		}
+
+		from := store
+		if archived, _ := cmd.Flags().GetBool("archived"); archived {
+			archive, err := openArchive()
+			if err != nil {
+				return err
+			}
+			defer archive.Close()
+			from = archive
+			if !cmd.Flags().Changed("completed") {
+				opts.Status = tasks.StatusAll
+			}
+		}
+
+		list, err := tasks.ListTasks(from, opts)
+		if err != nil {
+			return err
 		}
+		return printer.PrintTaskList(list)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(listCmd)
 	listCmd.Flags().BoolP("all", "a", false, "List all tasks")
+	listCmd.Flags().BoolP("completed", "c", false, "List completed tasks only")
+	listCmd.Flags().Bool("archived", false, "List archived tasks instead")
+	listCmd.Flags().Bool("overdue", false, "Only list open tasks past their due date")
+	listCmd.Flags().String("due-within", "", "Only list open tasks due within a duration, e.g. 3d or 12h")
+	listCmd.Flags().String("sort", tasks.SortByID, "Sort keys, comma separated; prefix with - to reverse, e.g. due or -priority")
+	listCmd.Flags().StringSlice("tag", nil, "Only list tasks with this tag, may be repeated")
+	listCmd.Flags().String("project", "", "Only list tasks in this project")
+	listCmd.Flags().String("priority", "", "Only list tasks with this priority")
+	listCmd.Flags().String("group-by", "", "Group the output by project, tag or priority")
+	listCmd.Flags().Bool("tree", false, "Show subtasks under their parent tasks")
 	// Here you will define your flags and configuration settings.
 
 	// Cobra supports Persistent Flags which will work for this command
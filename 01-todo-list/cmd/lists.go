@@ -0,0 +1,42 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/spf13/cobra"
+)
+
+// listsCmd represents the lists command
+var listsCmd = &cobra.Command{
+	Use:   "lists",
+	Short: "Show your named task lists",
+	Long: `Show the named task lists kept in the data directory.
+	For example:
+	tasks lists
+
+	The current list is marked with *. Use --list to work on another one:
+	tasks --list work add "Review PR"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names, err := tasks.Lists(storeKind)
+		if err != nil {
+			return err
+		}
+		return printer.PrintLists(names, listName)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listsCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// listsCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// listsCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
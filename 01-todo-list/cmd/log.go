@@ -0,0 +1,63 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/spf13/cobra"
+)
+
+// logCmd represents the log command
+var logCmd = &cobra.Command{
+	Use:   "log <id> <duration>",
+	Short: "Record time spent on a task",
+	Long: `Record time spent on a task without a timer. The time counts as
+	just spent, or as spent on the day given with --date.
+	For example:
+	tasks log 3 1h30m
+	tasks log 3 45m --date yesterday
+	tasks log 3 2h --date 2026-10-12`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := parseID(args[0])
+		if err != nil {
+			return err
+		}
+		spent, err := tasks.ParseDuration(args[1])
+		if err != nil {
+			return err
+		}
+		var day time.Time
+		if value, _ := cmd.Flags().GetString("date"); value != "" {
+			if day, err = tasks.ParseDay(value, time.Now()); err != nil {
+				return err
+			}
+		}
+
+		task, err := tasks.LogTime(store, id, spent, day)
+		if err != nil {
+			return err
+		}
+		message := fmt.Sprintf("Logged %s on task %d, %s spent in all", tasks.FormatSpent(spent), id, tasks.FormatSpent(task.Elapsed(time.Now())))
+		return printer.PrintTask(task, message)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logCmd)
+	logCmd.Flags().String("date", "", "day the time was spent, such as yesterday or 2026-10-12")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// logCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// logCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
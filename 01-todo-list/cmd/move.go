@@ -0,0 +1,78 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/spf13/cobra"
+)
+
+// moveCmd represents the move command
+var moveCmd = &cobra.Command{
+	Use:   "move <id> <state>",
+	Short: "Move a task to another workflow state",
+	Long: `Move a task to another state of the workflow, by default todo,
+	in-progress, review and done. Moving a task to done completes it and
+	moving it out of done reopens it. Each move is recorded with its time.
+	For example:
+	tasks move 3 review
+	tasks move 3 done
+	tasks move 3 todo
+
+	The states between todo and done are set with tasks workflow.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := parseID(args[0])
+		if err != nil {
+			return err
+		}
+		workflow, err := tasks.LoadWorkflow(dataPath)
+		if err != nil {
+			return err
+		}
+		force, _ := cmd.Flags().GetBool("force")
+		return moveTask(workflow, id, args[1], force)
+	},
+}
+
+// moveTask moves a task to state and reports where it went.
+func moveTask(workflow tasks.Workflow, id int, state string, force bool) error {
+	before, err := store.Get(id)
+	if err != nil {
+		return err
+	}
+	task, next, err := tasks.MoveTask(store, workflow, id, state, force)
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Task %d moved from %s to %s", id, before.WorkflowState(), state)
+	if before.WorkflowState() == state {
+		message = fmt.Sprintf("Task %d is already in %s", id, state)
+	}
+	if err := printer.PrintTask(task, message); err != nil {
+		return err
+	}
+	if next != nil {
+		printer.PrintNote(fmt.Sprintf("Next occurrence added as task %d, due %s", next.ID, next.Due.Format("Mon 2006-01-02 15:04")))
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(moveCmd)
+	moveCmd.Flags().Bool("force", false, "Move to done even if the task has open subtasks")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// moveCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// moveCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
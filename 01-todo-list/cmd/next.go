@@ -0,0 +1,57 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"strings"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/spf13/cobra"
+)
+
+// nextCmd represents the next command
+var nextCmd = &cobra.Command{
+	Use:   "next [query]",
+	Short: "List the tasks you can work on now",
+	Long: `List the open tasks that can be started right away: those without open
+	subtasks and whose blockers are all done. The most important and most
+	urgent come first.
+	For example:
+	tasks next
+	tasks next -n 3
+	tasks next "project:web"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list, err := tasks.ListTasks(store, tasks.ListOptions{
+			Status:     tasks.StatusOpen,
+			Query:      strings.Join(args, " "),
+			Actionable: true,
+			SortBy:     "-priority,due,id",
+		})
+		if err != nil {
+			return err
+		}
+		if limit, _ := cmd.Flags().GetInt("limit"); limit > 0 {
+			for i := range list.Groups {
+				group := &list.Groups[i]
+				group.Tasks = group.Tasks[:min(limit, len(group.Tasks))]
+			}
+		}
+		return printer.PrintTaskList(list)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(nextCmd)
+	nextCmd.Flags().IntP("limit", "n", 0, "Show at most this many tasks")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// nextCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// nextCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
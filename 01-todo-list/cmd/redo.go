@@ -0,0 +1,42 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// redoCmd represents the redo command
+var redoCmd = &cobra.Command{
+	Use:   "redo",
+	Short: "Redo the last undone command",
+	Long: `Apply the last command taken back with undo again.
+	For example:
+	tasks undo
+	tasks redo
+
+	Any new change after an undo starts a fresh history and cannot be redone past.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		changes, err := journal.Redo()
+		if err != nil {
+			return err
+		}
+		return printRewind(changes, "Redid")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(redoCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// redoCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// redoCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/spf13/cobra"
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report [query]",
+	Short: "Sum up the time spent on tasks",
+	Long: `Sum up the time tracked with tasks start and tasks log, by task, by
+	tag and by day. Archived tasks count too.
+	For example:
+	tasks report --week
+	tasks report --since 2026-10-01 --by tag
+	tasks report --week project:web
+
+	A query narrows down the tasks as in tasks list.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		now := time.Now()
+		var from time.Time
+		week, _ := cmd.Flags().GetBool("week")
+		since, _ := cmd.Flags().GetString("since")
+		switch {
+		case week && since != "":
+			return fmt.Errorf("use either --week or --since")
+		case week:
+			from = tasks.StartOfWeek(now)
+		case since != "":
+			var err error
+			if from, err = tasks.ParseDay(since, now); err != nil {
+				return err
+			}
+		}
+		by, _ := cmd.Flags().GetStringSlice("by")
+		for _, group := range by {
+			if group != "task" && group != "tag" && group != "day" {
+				return fmt.Errorf("cannot report by %q (want task, tag or day)", group)
+			}
+		}
+
+		opts := tasks.ListOptions{Status: tasks.StatusAll, Query: strings.Join(args, " ")}
+		list, err := tasks.ListTasks(store, opts)
+		if err != nil {
+			return err
+		}
+		all := list.All()
+		if _, err := os.Stat(tasks.ArchivePath(dataPath)); err == nil {
+			archive, err := openArchive()
+			if err != nil {
+				return err
+			}
+			defer archive.Close()
+			archived, err := tasks.ListTasks(archive, opts)
+			if err != nil {
+				return err
+			}
+			all = append(all, archived.All()...)
+		}
+
+		report := tasks.ReportTime(all, from, now)
+		if !slices.Contains(by, "task") {
+			report.ByTask = nil
+		}
+		if !slices.Contains(by, "tag") {
+			report.ByTag = nil
+		}
+		if !slices.Contains(by, "day") {
+			report.ByDay = nil
+		}
+		return printer.PrintTimeReport(report)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.Flags().Bool("week", false, "only this week, from Monday")
+	reportCmd.Flags().String("since", "", "only from this day on, such as monday or 2026-10-01")
+	reportCmd.Flags().StringSlice("by", []string{"task", "tag", "day"}, "parts of the report: task, tag and day")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// reportCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// reportCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -0,0 +1,54 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/spf13/cobra"
+)
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore <id>",
+	Short: "Bring an archived task back into the list",
+	Long: `Move an archived task back into the live list, keeping its ID.
+	For example:
+	tasks list --archived
+	tasks restore 3`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskId, err := parseID(args[0])
+		if err != nil {
+			return err
+		}
+
+		archive, err := openArchive()
+		if err != nil {
+			return err
+		}
+		defer archive.Close()
+
+		task, err := tasks.RestoreTask(journal.Unwrap(), archive, taskId)
+		if err != nil {
+			return err
+		}
+		return printer.PrintTask(task, fmt.Sprintf("Task %d restored", task.ID))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// restoreCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// restoreCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -4,24 +4,170 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
+// store is opened before every command runs and closed afterwards
+var store tasks.Store
+
+// journal is store itself, recording the changes each command makes so
+// they can be undone
+var journal *tasks.JournaledStore
+
+// printer writes command output in the format chosen with --output
+var printer tasks.Printer
+
+// dataPath is the data file store was opened on
+var dataPath string
+
+// readOnly names the commands that only read the tasks; they are not
+// followed by an automatic archive, which would write the data file
+var readOnly = map[string]bool{
+	"board":   true,
+	"daemon":  true,
+	"doctor":  true,
+	"export":  true,
+	"history": true,
+	"list":    true,
+	"lists":   true,
+	"next":    true,
+	"report":  true,
+	"search":  true,
+}
+
+// Values of the global flags
+var (
+	storeKind    string
+	dataFile     string
+	listName     string
+	outputFormat string
+)
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "tasks",
-	Short: "A brief description of your application",
-	Long: `A longer description that spans multiple lines and likely contains
-examples and usage of using your application. For example:
+	Short: "Manage your TODO List from the terminal",
+	Long: `Manage your TODO List from the terminal.
+
+Run tasks without a command to open the full-screen interface, or use the
+commands below from scripts and the shell. For example:
+tasks add "Learn Go" --due friday
+tasks list
+tasks complete 1`,
+	RunE:         runUI,
+	SilenceUsage: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		printer = tasks.Printer{Out: cmd.OutOrStdout(), Format: outputFormat}
+		if f, ok := printer.Out.(*os.File); ok && os.Getenv("NO_COLOR") == "" {
+			printer.Color = isatty.IsTerminal(f.Fd())
+		}
+		if err := printer.Validate(); err != nil {
+			return err
+		}
+
+		var err error
+		if dataPath, err = storePath(); err != nil {
+			return err
+		}
+		opened, err := tasks.OpenStore(storeKind, dataPath)
+		if err != nil {
+			return err
+		}
+		journal = tasks.NewJournaledStore(opened, tasks.JournalPath(dataPath), cmd.Name())
+		store = journal
+		return nil
+	},
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		if readOnly[cmd.Name()] {
+			return store.Close()
+		}
+		if err := autoArchive(); err != nil {
+			_ = store.Close()
+			return err
+		}
+		return store.Close()
+	},
+}
 
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
-	// Uncomment the following line if your bare application
-	// has an action associated with it:
-	// Run: func(cmd *cobra.Command, args []string) { },
+// openArchive opens the archive kept beside the data file.
+func openArchive() (tasks.Store, error) {
+	return tasks.OpenStore(storeKind, tasks.ArchivePath(dataPath))
+}
+
+// autoArchive moves tasks completed long enough ago into the archive once
+// tasks archive --auto-after has been set for the data file.
+func autoArchive() error {
+	after, err := tasks.AutoArchiveAfter(dataPath)
+	if err != nil || after == 0 {
+		return err
+	}
+	archive, err := openArchive()
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+	_, err = tasks.AutoArchive(journal.Unwrap(), archive, after)
+	return err
+}
+
+// storePath picks the data file: --file, then $TASKS_FILE, then the
+// named list in the XDG data directory.
+func storePath() (string, error) {
+	if dataFile != "" {
+		return dataFile, nil
+	}
+	if env := os.Getenv("TASKS_FILE"); env != "" {
+		return env, nil
+	}
+	return tasks.ListPath(storeKind, listName)
+}
+
+// parseID reads a task ID given on the command line.
+func parseID(arg string) (int, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("invalid task ID %q", arg)
+	}
+	return id, nil
+}
+
+// parseIDs reads task IDs given as separate arguments, comma separated
+// lists or ranges such as 7-10.
+func parseIDs(args []string) ([]int, error) {
+	var ids []int
+	for _, arg := range args {
+		for _, part := range strings.Split(arg, ",") {
+			from, to, isRange := strings.Cut(part, "-")
+			if !isRange {
+				id, err := parseID(part)
+				if err != nil {
+					return nil, err
+				}
+				ids = append(ids, id)
+				continue
+			}
+
+			first, err := parseID(from)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ID range %q", part)
+			}
+			last, err := parseID(to)
+			if err != nil || last < first {
+				return nil, fmt.Errorf("invalid ID range %q", part)
+			}
+			for id := first; id <= last; id++ {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids, nil
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -39,6 +185,11 @@ func init() {
 	// will be global for your application.
 
 	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.01-todo-list.yaml)")
+	rootCmd.PersistentFlags().StringVar(&storeKind, "store", tasks.StoreCSV, "storage backend (csv or sqlite)")
+	rootCmd.PersistentFlags().StringVar(&dataFile, "file", "", "data file to use, overrides --list (default is $TASKS_FILE)")
+	rootCmd.PersistentFlags().StringVarP(&listName, "list", "l", tasks.DefaultList, "named task list in ~/.local/share/tasks")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", tasks.OutputTable, "output format: table, json, jsonl, csv or yaml")
+	rootCmd.PersistentFlags().DurationVar(&tasks.LockTimeout, "lock-timeout", tasks.LockTimeout, "how long to wait for another command holding the data file, 0 to fail at once")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -0,0 +1,80 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/spf13/cobra"
+)
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search task descriptions, archived tasks included",
+	Long: `Search the descriptions of every task, open, completed and archived,
+	and show the best matches first. Tasks that match more closely, or were
+	created or completed more recently, rank higher. Queries without capital
+	letters ignore case.
+	For example:
+	tasks search invoice
+	tasks search --fuzzy qrtrpt
+	tasks search --regex "^(call|email) "`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fuzzy, _ := cmd.Flags().GetBool("fuzzy")
+		regex, _ := cmd.Flags().GetBool("regex")
+		limit, _ := cmd.Flags().GetInt("limit")
+		mode := tasks.SearchSubstring
+		switch {
+		case fuzzy && regex:
+			return errors.New("use either --fuzzy or --regex")
+		case fuzzy:
+			mode = tasks.SearchFuzzy
+		case regex:
+			mode = tasks.SearchRegex
+		}
+
+		var archive tasks.Store
+		if active, _ := cmd.Flags().GetBool("active"); !active {
+			if _, err := os.Stat(tasks.ArchivePath(dataPath)); err == nil {
+				if archive, err = openArchive(); err != nil {
+					return err
+				}
+				defer archive.Close()
+			}
+		}
+
+		results, err := tasks.SearchTasks(store, archive, strings.Join(args, " "), mode, limit)
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 && printer.Format == tasks.OutputTable {
+			printer.PrintNote("No matching tasks")
+			return nil
+		}
+		return printer.PrintSearchResults(results)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().Bool("fuzzy", false, "match the letters of the query in order, with anything in between")
+	searchCmd.Flags().Bool("regex", false, "treat the query as a regular expression")
+	searchCmd.Flags().Bool("active", false, "leave out archived tasks")
+	searchCmd.Flags().IntP("limit", "n", 20, "number of results to show, 0 for all")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// searchCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// searchCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -0,0 +1,101 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/spf13/cobra"
+)
+
+// startCmd represents the start command
+var startCmd = &cobra.Command{
+	Use:   "start <id>",
+	Short: "Start working on a task and time it",
+	Long: `Move a task out of todo to the next workflow state, in-progress
+	unless the workflow was changed, and start a timer on it. Tasks past
+	todo stay in their state. Time is tracked on one task at a time, so
+	the timer of any other task stops.
+	For example:
+	tasks start 3
+	tasks start 3 --no-timer
+
+	Stop the timer with tasks stop; completing the task stops it too.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := parseID(args[0])
+		if err != nil {
+			return err
+		}
+		workflow, err := tasks.LoadWorkflow(dataPath)
+		if err != nil {
+			return err
+		}
+		noTimer, _ := cmd.Flags().GetBool("no-timer")
+
+		// Moving the task and starting its timer are one change, undone
+		// together
+		var (
+			task     tasks.Tasks
+			stopped  []tasks.Tasks
+			messages []string
+		)
+		err = store.Batch(func(tx tasks.Store) error {
+			var err error
+			if task, err = tx.Get(id); err != nil {
+				return err
+			}
+			// Tasks already under way stay where they are
+			if task.WorkflowState() == tasks.StateTodo {
+				started, err := workflow.Started()
+				switch {
+				case err == nil:
+					if task, _, err = tasks.MoveTask(tx, workflow, id, started, false); err != nil {
+						return err
+					}
+					messages = append(messages, fmt.Sprintf("Task %d moved from %s to %s", id, tasks.StateTodo, started))
+				case noTimer:
+					return err
+				}
+			}
+			if noTimer {
+				return nil
+			}
+			task, stopped, err = tasks.StartTimer(tx, id)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, other := range stopped {
+			printer.PrintNote(fmt.Sprintf("Timer stopped on task %d, %s spent in all", other.ID, tasks.FormatSpent(other.Elapsed(time.Now()))))
+		}
+		switch {
+		case !noTimer:
+			messages = append(messages, fmt.Sprintf("Timer running on task %d", id))
+		case len(messages) == 0:
+			messages = append(messages, fmt.Sprintf("Task %d is already in %s", id, task.WorkflowState()))
+		}
+		return printer.PrintTask(task, strings.Join(messages, "\n"))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(startCmd)
+	startCmd.Flags().Bool("no-timer", false, "only move the task, without timing it")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// startCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// startCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
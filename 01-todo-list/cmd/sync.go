@@ -0,0 +1,148 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Synchronize the list through a git repository",
+	Long: `Share the list between machines through a git repository. Sync pulls
+	the tasks pushed from elsewhere, merges them task by task with the ones
+	here and pushes the result as a new commit. Tasks changed on one side
+	only are taken from that side; tasks changed on both are conflicts, which
+	are asked about on a terminal and otherwise go to the version changed
+	last. Tasks added on both sides under the same ID are both kept, the
+	one added here getting a new ID.
+	For example:
+	tasks sync --remote git@example.com:me/tasks.git
+	tasks sync
+	tasks sync --conflicts remote
+
+	The repository is cloned beside the data file, which it holds as CSV
+	whatever the store, so several lists can share it. Sync cannot be
+	undone with tasks undo.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if remote, _ := cmd.Flags().GetString("remote"); remote != "" {
+			if err := tasks.SetupSync(dataPath, remote); err != nil {
+				return err
+			}
+		}
+
+		conflicts, _ := cmd.Flags().GetString("conflicts")
+		var resolve tasks.Resolver
+		switch conflicts {
+		case "ask":
+			resolve = tasks.KeepNewer
+			if f, ok := cmd.InOrStdin().(*os.File); ok && isatty.IsTerminal(f.Fd()) {
+				resolve = askConflict(cmd.InOrStdin(), cmd.ErrOrStderr())
+			}
+		case "newer":
+			resolve = tasks.KeepNewer
+		case "local":
+			resolve = tasks.KeepLocal
+		case "remote":
+			resolve = tasks.KeepRemote
+		default:
+			return fmt.Errorf("invalid --conflicts %q (want ask, newer, local or remote)", conflicts)
+		}
+
+		result, err := tasks.Sync(journal.Unwrap(), dataPath, resolve)
+		if errors.Is(err, tasks.ErrNoRemote) {
+			return fmt.Errorf("%w, run tasks sync --remote URL first", err)
+		}
+		if err != nil {
+			return err
+		}
+		return printer.PrintSyncResult(result)
+	},
+}
+
+// askConflict shows both versions of each conflicting task and asks which
+// to keep. Just pressing enter keeps the one changed last.
+func askConflict(in io.Reader, out io.Writer) tasks.Resolver {
+	answers := bufio.NewReader(in)
+	return func(c tasks.Conflict) (*tasks.Tasks, error) {
+		fmt.Fprintf(out, "\nTask %d was changed both here and in the repository:\n", c.ID)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "\t\tLOCAL\tREMOTE")
+		switch {
+		case c.Local == nil:
+			fmt.Fprintf(w, "\t\tdeleted\t%s\n", c.Remote.Description)
+		case c.Remote == nil:
+			fmt.Fprintf(w, "\t\t%s\tdeleted\n", c.Local.Description)
+		default:
+			for _, field := range c.Fields() {
+				fmt.Fprintf(w, "\t%s\t%s\t%s\n", field.Name, orDash(field.Local), orDash(field.Remote))
+			}
+			fmt.Fprintf(w, "\tchanged\t%s\t%s\n", changedAt(c.Local), changedAt(c.Remote))
+		}
+		w.Flush()
+
+		newer, _ := tasks.KeepNewer(c)
+		prompt := "Keep [L]ocal or [r]emote version? "
+		if newer == c.Remote {
+			prompt = "Keep [l]ocal or [R]emote version? "
+		}
+		for {
+			fmt.Fprint(out, prompt)
+			answer, err := answers.ReadString('\n')
+			if err != nil && answer == "" {
+				return nil, errors.New("sync cancelled, nothing was changed")
+			}
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "":
+				return newer, nil
+			case "l", "local":
+				return c.Local, nil
+			case "r", "remote":
+				return c.Remote, nil
+			}
+		}
+	}
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func changedAt(task *tasks.Tasks) string {
+	if task.UpdatedAt.IsZero() {
+		return "-"
+	}
+	return task.UpdatedAt.Local().Format("2006-01-02 15:04")
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.Flags().String("remote", "", "git repository to sync with, cloned the first time")
+	syncCmd.Flags().String("conflicts", "ask", "how to settle tasks changed on both sides: ask, newer, local or remote")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// syncCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// syncCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
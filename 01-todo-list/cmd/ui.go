@@ -0,0 +1,64 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"errors"
+	"os"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/0xirvan/goprojects/01-todo-list/tui"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+// uiCmd represents the ui command
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Manage tasks in a full-screen terminal interface",
+	Long: `Open a full-screen interface to browse and triage tasks without
+	remembering their IDs. Running tasks without a command does the same.
+
+	Move with the arrow keys or j and k, then:
+	a  add a task, with +tags and @project as on the command line
+	e  edit the selected task
+	space  complete or reopen it
+	p  change its priority
+	d  delete it
+	/  filter with a query, as tasks list takes
+	tab  switch between open, all and completed tasks
+	q  quit
+
+	Every change is journaled, so tasks undo reverts them one by one.`,
+	Args: cobra.NoArgs,
+	RunE: runUI,
+}
+
+// runUI opens the terminal interface. Without a terminal, as when output is
+// piped, the bare tasks command shows its help instead.
+func runUI(cmd *cobra.Command, args []string) error {
+	if !isatty.IsTerminal(os.Stdin.Fd()) || !isatty.IsTerminal(os.Stdout.Fd()) {
+		if !cmd.HasParent() {
+			return cmd.Help()
+		}
+		return errors.New("tasks ui needs a terminal")
+	}
+	return tui.Run(store, func(action string) tasks.Store {
+		return journal.Action(action)
+	})
+}
+
+func init() {
+	rootCmd.AddCommand(uiCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// uiCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// uiCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
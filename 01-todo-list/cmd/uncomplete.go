@@ -0,0 +1,47 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/spf13/cobra"
+)
+
+// uncompleteCmd represents the uncomplete command
+var uncompleteCmd = &cobra.Command{
+	Use:   "uncomplete [id...]",
+	Short: "Reopen a completed task",
+	Long: `Reopen a completed task by providing the task ID.
+	For example:
+	tasks uncomplete 1
+	
+	This will mark the task with ID 1 as not completed again.
+
+	Several tasks can be given by ID or range, or picked with filters:
+	tasks uncomplete 3-5
+	tasks uncomplete --where "completed>-1d"`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := selectionFromFlags(cmd, args, tasks.StatusDone)
+		if err != nil {
+			return err
+		}
+		return runBulk(cmd, opts, tasks.UncompleteTasks, "reopen", "reopened")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uncompleteCmd)
+	addSelectionFlags(uncompleteCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// uncompleteCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// uncompleteCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
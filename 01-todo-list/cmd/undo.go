@@ -0,0 +1,74 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/spf13/cobra"
+)
+
+// undoCmd represents the undo command
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Undo the last command that changed tasks",
+	Long: `Undo the last add, complete, edit, delete or other command that changed
+	tasks, restoring them as they were. Run it again to go further back.
+	For example:
+	tasks delete 3
+	tasks undo
+
+	Every change is kept in a journal next to the data file; see tasks history.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		changes, err := journal.Undo()
+		if err != nil {
+			return err
+		}
+		return printRewind(changes, "Undid")
+	},
+}
+
+// printRewind reports the tasks an undo or redo touched, as they are now.
+// A task changed several times by the command is shown once.
+func printRewind(changes []tasks.JournalEntry, verb string) error {
+	var changed []tasks.Tasks
+	index := make(map[int]int) // position of each task in changed
+	for _, change := range changes {
+		now, other := change.Before, change.After
+		if verb != "Undid" {
+			now, other = other, now
+		}
+		if now == nil {
+			now = other // the task no longer exists, show it as it was
+		}
+		i, seen := index[now.ID]
+		switch {
+		case !seen:
+			index[now.ID] = len(changed)
+			changed = append(changed, *now)
+		case verb != "Undid":
+			changed[i] = *now // redo leaves the task as its last change did
+		}
+	}
+	if len(changed) == 1 {
+		return printer.PrintTask(changed[0], fmt.Sprintf("%s %s of task %d", verb, changes[0].Action, changed[0].ID))
+	}
+	return printer.PrintTasks(changed, fmt.Sprintf("%s %s of %s", verb, changes[0].Action, countTasks(len(changed))))
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// undoCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// undoCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -0,0 +1,70 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/spf13/cobra"
+)
+
+// workflowCmd represents the workflow command
+var workflowCmd = &cobra.Command{
+	Use:   "workflow [state...]",
+	Short: "Show or change the workflow states",
+	Long: `Show the states tasks move through, or set the ones between todo and
+	done. Every list keeps its own workflow.
+	For example:
+	tasks workflow
+	tasks workflow doing review qa
+	tasks workflow --reset
+
+	Tasks left in a state the workflow no longer has keep it, and show up
+	in a column of their own on the board until they are moved.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reset, _ := cmd.Flags().GetBool("reset")
+		if reset && len(args) > 0 {
+			return fmt.Errorf("give either states or --reset")
+		}
+
+		switch {
+		case reset:
+			if err := tasks.SaveWorkflow(dataPath, nil); err != nil {
+				return err
+			}
+		case len(args) > 0:
+			workflow, err := tasks.NewWorkflow(args)
+			if err != nil {
+				return err
+			}
+			if err := tasks.SaveWorkflow(dataPath, workflow); err != nil {
+				return err
+			}
+		}
+
+		workflow, err := tasks.LoadWorkflow(dataPath)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), strings.Join(workflow, " → "))
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(workflowCmd)
+	workflowCmd.Flags().Bool("reset", false, "go back to todo, in-progress, review and done")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// workflowCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// workflowCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
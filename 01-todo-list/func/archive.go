@@ -0,0 +1,153 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// ArchivePath is where the archive of a data file is kept. The archive
+// uses the same backend as the data file.
+func ArchivePath(dataPath string) string {
+	return dataPath + ".archive"
+}
+
+// autoArchivePath holds the --auto-after setting of a data file.
+func autoArchivePath(dataPath string) string {
+	return dataPath + ".archive-after"
+}
+
+// ArchiveTasks moves the completed tasks selected by opts from s into
+// archive, returning them. Open tasks named by ID are refused. With dryRun
+// set nothing is moved.
+//
+// The archive is written before the tasks leave s, so a crash in between
+// leaves a task in both rather than in neither; archiving it again simply
+// replaces the archived copy. Subtasks left behind move up to the parent of
+// the archived task and blockers archived are dropped, as on deletion.
+// Archiving is not journaled, so the tasks it writes are marked as changed
+// here.
+func ArchiveTasks(s, archive Store, opts ListOptions, dryRun bool) ([]Tasks, error) {
+	if dryRun {
+		return findArchivable(s, opts)
+	}
+	s, archive = touchingStore{s}, touchingStore{archive}
+
+	var moved []Tasks
+	err := s.Batch(func(tx Store) error {
+		var err error
+		if moved, err = findArchivable(tx, opts); err != nil || len(moved) == 0 {
+			return err
+		}
+		err = archive.Batch(func(atx Store) error {
+			for _, task := range moved {
+				if err := upsert(atx, task); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+		for _, task := range moved {
+			if _, err := deleteLinked(tx, task); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
+
+func findArchivable(s Store, opts ListOptions) ([]Tasks, error) {
+	selected, err := findSelected(s, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range selected {
+		if !task.Completed() {
+			return nil, fmt.Errorf("task %d is not completed", task.ID)
+		}
+	}
+	return selected, nil
+}
+
+// RestoreTask moves an archived task back into s, keeping its ID.
+//
+// Like ArchiveTasks it locks s before the archive, so the two cannot
+// deadlock, and the task is written to s before it leaves the archive;
+// restoring it again after a crash in between replaces the copy in s. The
+// restored task counts as changed now.
+func RestoreTask(s, archive Store, id int) (Tasks, error) {
+	var task Tasks
+	err := s.Batch(func(tx Store) error {
+		var err error
+		if task, err = archive.Get(id); err != nil {
+			return err
+		}
+		task = touch(task)
+		return upsert(tx, task)
+	})
+	if err != nil {
+		return Tasks{}, err
+	}
+	if err := archive.Delete(id); err != nil {
+		return Tasks{}, err
+	}
+	return task, nil
+}
+
+// upsert stores task under its own ID, replacing any task already there.
+func upsert(s Store, task Tasks) error {
+	err := s.Update(task)
+	if errors.Is(err, ErrNotFound) {
+		_, err = s.Create(task)
+	}
+	return err
+}
+
+// AutoArchiveAfter returns how long completed tasks of the data file stay
+// in the live list before AutoArchive moves them, zero when it is off.
+func AutoArchiveAfter(dataPath string) (time.Duration, error) {
+	data, err := os.ReadFile(autoArchivePath(dataPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return ParseDuration(strings.TrimSpace(string(data)))
+}
+
+// SetAutoArchiveAfter saves the auto archive setting; zero turns it off.
+func SetAutoArchiveAfter(dataPath string, after time.Duration) error {
+	if after <= 0 {
+		err := os.Remove(autoArchivePath(dataPath))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	return writeFileAtomic(autoArchivePath(dataPath), func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, after)
+		return err
+	})
+}
+
+// AutoArchive archives the tasks completed longer ago than after. Nothing
+// is written when no task qualifies.
+func AutoArchive(s, archive Store, after time.Duration) ([]Tasks, error) {
+	opts := ListOptions{Status: StatusDone, OlderThan: after}
+	due, err := findArchivable(s, opts)
+	if err != nil || len(due) == 0 {
+		return nil, err
+	}
+	return ArchiveTasks(s, archive, opts, false)
+}
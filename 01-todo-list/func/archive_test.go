@@ -0,0 +1,94 @@
+package tasks_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+)
+
+func TestArchiveAndRestore(t *testing.T) {
+	now := time.Now()
+	live := tasks.NewMemoryStore(
+		tasks.Tasks{ID: 1, Description: "long done", CreatedAt: now, CompletedAt: now.AddDate(0, 0, -20)},
+		tasks.Tasks{ID: 2, Description: "just done", CreatedAt: now, CompletedAt: now},
+		tasks.Tasks{ID: 3, Description: "open", CreatedAt: now},
+	)
+	archive := tasks.NewMemoryStore()
+
+	if _, err := tasks.ArchiveTasks(live, archive, tasks.ListOptions{IDs: []int{3}}, false); err == nil {
+		t.Fatal("expected error archiving an open task")
+	}
+
+	moved, err := tasks.AutoArchive(live, archive, 14*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(moved) != 1 || moved[0].ID != 1 {
+		t.Fatalf("auto archived %+v", moved)
+	}
+	if _, err := live.Get(1); !errors.Is(err, tasks.ErrNotFound) {
+		t.Fatal("task 1 still live")
+	}
+
+	if _, err := tasks.ArchiveTasks(live, archive, tasks.ListOptions{Status: tasks.StatusDone}, false); err != nil {
+		t.Fatal(err)
+	}
+	archived, _ := archive.List()
+	if len(archived) != 2 {
+		t.Fatalf("archive holds %+v", archived)
+	}
+
+	task, err := tasks.RestoreTask(live, archive, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Description != "long done" {
+		t.Fatalf("restored %+v", task)
+	}
+	if restored, err := live.Get(1); err != nil || restored.UpdatedAt.Before(now) {
+		t.Fatalf("task 1 not restored as changed now: %+v, %v", restored, err)
+	}
+	if _, err := archive.Get(1); !errors.Is(err, tasks.ErrNotFound) {
+		t.Fatal("task 1 still archived")
+	}
+}
+
+func TestArchiveUnlinks(t *testing.T) {
+	now := time.Now()
+	live := tasks.NewMemoryStore(
+		tasks.Tasks{ID: 1, Description: "done parent", CreatedAt: now, CompletedAt: now},
+		tasks.Tasks{ID: 2, Description: "open child", CreatedAt: now, Parent: 1},
+		tasks.Tasks{ID: 3, Description: "was blocked", CreatedAt: now, BlockedBy: []int{1}},
+	)
+	if _, err := tasks.ArchiveTasks(live, tasks.NewMemoryStore(), tasks.ListOptions{IDs: []int{1}}, false); err != nil {
+		t.Fatal(err)
+	}
+	if task, _ := live.Get(2); task.Parent != 0 {
+		t.Errorf("task 2 still has archived parent %d", task.Parent)
+	}
+	if task, _ := live.Get(3); len(task.BlockedBy) != 0 {
+		t.Errorf("task 3 still blocked by %v", task.BlockedBy)
+	}
+}
+
+func TestAutoArchiveSetting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.csv")
+	if after, err := tasks.AutoArchiveAfter(path); err != nil || after != 0 {
+		t.Fatalf("got %v %v before setting", after, err)
+	}
+	if err := tasks.SetAutoArchiveAfter(path, 14*24*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if after, err := tasks.AutoArchiveAfter(path); err != nil || after != 14*24*time.Hour {
+		t.Fatalf("got %v %v", after, err)
+	}
+	if err := tasks.SetAutoArchiveAfter(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := tasks.AutoArchiveAfter(path); after != 0 {
+		t.Fatalf("still set to %v", after)
+	}
+}
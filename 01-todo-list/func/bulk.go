@@ -0,0 +1,117 @@
+package tasks
+
+import "time"
+
+// CompleteTasks marks every task selected by opts as done in one
+// transaction, returning them and the next occurrences of recurring ones.
+// With dryRun set nothing is changed and the tasks that would have been
+// are returned instead. Tasks with open subtasks that are not selected too
+// are refused unless force is set.
+func CompleteTasks(s Store, opts ListOptions, dryRun, force bool) (done, next []Tasks, err error) {
+	now := time.Now()
+	check := checkSubtasks
+	if force {
+		check = nil
+	}
+	done, err = bulk(s, opts, dryRun, check, func(tx Store, task Tasks) (Tasks, error) {
+		task, spawned, err := completeTask(tx, task, now)
+		if spawned != nil {
+			next = append(next, *spawned)
+		}
+		return task, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return done, next, nil
+}
+
+// UncompleteTasks reopens every task selected by opts, as CompleteTasks.
+func UncompleteTasks(s Store, opts ListOptions, dryRun bool) ([]Tasks, error) {
+	now := time.Now()
+	return bulk(s, opts, dryRun, nil, func(tx Store, task Tasks) (Tasks, error) {
+		if task.Completed() {
+			task.CompletedAt = time.Time{}
+			task.enter(StateTodo, now)
+		}
+		return task, tx.Update(task)
+	})
+}
+
+// DeleteTasks removes every task selected by opts, as CompleteTasks, and
+// unlinks them as DeleteTask does.
+func DeleteTasks(s Store, opts ListOptions, dryRun bool) ([]Tasks, error) {
+	return bulk(s, opts, dryRun, nil, deleteLinked)
+}
+
+// deleteLinked deletes a task and unlinks it. The task is read again first:
+// deleting its parent earlier in the batch moved it up a level.
+func deleteLinked(tx Store, task Tasks) (Tasks, error) {
+	task, err := tx.Get(task.ID)
+	if err != nil {
+		return Tasks{}, err
+	}
+	if err := tx.Delete(task.ID); err != nil {
+		return Tasks{}, err
+	}
+	return task, unlink(tx, task)
+}
+
+// bulk selects tasks and applies change to each inside one batch, so either
+// all of them change or none do. check, when set, may refuse the selection
+// before anything changes, dry runs included.
+func bulk(s Store, opts ListOptions, dryRun bool, check func(s Store, selected []Tasks) error, change func(tx Store, task Tasks) (Tasks, error)) ([]Tasks, error) {
+	if dryRun {
+		selected, err := findSelected(s, opts)
+		if err == nil && check != nil {
+			err = check(s, selected)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return selected, nil
+	}
+
+	var selected []Tasks
+	err := s.Batch(func(tx Store) error {
+		var err error
+		if selected, err = findSelected(tx, opts); err != nil {
+			return err
+		}
+		if check != nil {
+			if err := check(tx, selected); err != nil {
+				return err
+			}
+		}
+		for i, task := range selected {
+			if selected[i], err = change(tx, task); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return selected, nil
+}
+
+// findSelected returns the tasks matching opts. IDs named in opts must exist.
+func findSelected(s Store, opts ListOptions) ([]Tasks, error) {
+	tasks, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range opts.IDs {
+		if _, err := findTask(tasks, id); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	opts, q, err := opts.resolve(now)
+	if err != nil {
+		return nil, err
+	}
+	return opts.apply(tasks, q, now)
+}
@@ -0,0 +1,71 @@
+package tasks_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+)
+
+func TestBulkOperations(t *testing.T) {
+	now := time.Now()
+	s := tasks.NewMemoryStore(
+		tasks.Tasks{ID: 1, Description: "old done", CreatedAt: now, CompletedAt: now.AddDate(0, 0, -40)},
+		tasks.Tasks{ID: 2, Description: "recent done", CreatedAt: now, CompletedAt: now.AddDate(0, 0, -2)},
+		tasks.Tasks{ID: 3, Description: "open", CreatedAt: now, Tags: []string{"errands"}},
+		tasks.Tasks{ID: 4, Description: "open too", CreatedAt: now, Tags: []string{"errands"}},
+		tasks.Tasks{ID: 5, Description: "ancient open", CreatedAt: now.AddDate(-1, 0, 0)},
+	)
+
+	// A dry run reports without changing anything
+	old := tasks.ListOptions{Status: tasks.StatusDone, OlderThan: 30 * 24 * time.Hour}
+	preview, err := tasks.DeleteTasks(s, old, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preview) != 1 || preview[0].ID != 1 {
+		t.Fatalf("dry run selected %+v", preview)
+	}
+	if _, err := s.Get(1); err != nil {
+		t.Fatal("dry run deleted task 1")
+	}
+
+	if _, err := tasks.DeleteTasks(s, old, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(1); !errors.Is(err, tasks.ErrNotFound) {
+		t.Fatalf("task 1 not deleted: %v", err)
+	}
+
+	done, _, err := tasks.CompleteTasks(s, tasks.ListOptions{Status: tasks.StatusOpen, Tags: []string{"errands"}}, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != 2 || !done[0].Completed() || !done[1].Completed() {
+		t.Fatalf("completed %+v", done)
+	}
+
+	// An unknown ID fails the whole batch
+	_, err = tasks.UncompleteTasks(s, tasks.ListOptions{IDs: []int{2, 3, 99}}, false)
+	if !errors.Is(err, tasks.ErrNotFound) {
+		t.Fatalf("got %v, want ErrNotFound", err)
+	}
+	if task, _ := s.Get(3); !task.Completed() {
+		t.Fatal("task 3 reopened by a failed batch")
+	}
+}
+
+func TestDeleteTasksChain(t *testing.T) {
+	s := tasks.NewMemoryStore(
+		tasks.Tasks{ID: 1, Description: "grandparent"},
+		tasks.Tasks{ID: 2, Description: "parent", Parent: 1},
+		tasks.Tasks{ID: 3, Description: "child", Parent: 2, BlockedBy: []int{1}},
+	)
+	if _, err := tasks.DeleteTasks(s, tasks.ListOptions{IDs: []int{1, 2}}, false); err != nil {
+		t.Fatal(err)
+	}
+	if task, _ := s.Get(3); task.Parent != 0 || len(task.BlockedBy) != 0 {
+		t.Fatalf("task 3 left linked to deleted tasks: %+v", task)
+	}
+}
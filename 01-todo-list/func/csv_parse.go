@@ -0,0 +1,365 @@
+package tasks
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var csvHeader = []string{"ID", "Description", "CreatedAt", "CompletedAt", "Due", "Priority", "Tags", "Project", "Recur", "Series", "Parent", "BlockedBy", "State", "Transitions", "TimeLog", "UpdatedAt"}
+
+// legacyColumns maps header names from older files onto current columns.
+var legacyColumns = map[string]string{
+	"IsCompleted": "CompletedAt",
+}
+
+// requiredColumns must be present in every header.
+var requiredColumns = []string{"ID", "Description", "CreatedAt"}
+
+// csvLayout maps column names to their position in a file's header.
+type csvLayout map[string]int
+
+// parseHeader reads the header row. Files written before a column existed
+// simply lack it; older names are mapped by legacyColumns.
+func parseHeader(header []string) (csvLayout, error) {
+	layout := make(csvLayout)
+	for i, name := range header {
+		if legacy, ok := legacyColumns[name]; ok {
+			name = legacy
+		}
+		layout[name] = i
+	}
+	for _, name := range requiredColumns {
+		if _, ok := layout[name]; !ok {
+			return nil, &RecordError{Line: 1, Field: "header", Value: name, Err: errMissingHeader}
+		}
+	}
+	return layout, nil
+}
+
+// defaultLayout is assumed for files whose header is missing.
+func defaultLayout() csvLayout {
+	layout := make(csvLayout)
+	for i, name := range csvHeader {
+		layout[name] = i
+	}
+	return layout
+}
+
+// parseRecord turns one row into a task. Fields that fail to parse are left
+// zero and reported, so callers can either stop at the first problem or
+// repair the row. Files written before CompletedAt existed hold a boolean in
+// its place; completed rows from those files are dated with migratedAt.
+func (l csvLayout) parseRecord(record []string, line int, migratedAt time.Time) (Tasks, []*RecordError) {
+	var (
+		task Tasks
+		errs []*RecordError
+	)
+	field := func(name string) (string, bool) {
+		i, ok := l[name]
+		if !ok {
+			return "", true // column added after this file was written
+		}
+		if i >= len(record) {
+			errs = append(errs, &RecordError{Line: line, Field: name, Err: errMissingField})
+			return "", false
+		}
+		return record[i], true
+	}
+	bad := func(name, value string, err error) {
+		errs = append(errs, &RecordError{Line: line, Field: name, Value: value, Err: err})
+	}
+
+	if value, ok := field("ID"); ok {
+		id, err := strconv.Atoi(value)
+		if err != nil || id < 1 {
+			bad("ID", value, errors.New("invalid ID"))
+		}
+		task.ID = max(id, 0)
+	}
+	if value, ok := field("Description"); ok {
+		if value == "" {
+			bad("Description", value, errors.New("empty description"))
+		}
+		task.Description = value
+	}
+	if value, ok := field("CreatedAt"); ok {
+		createdAt, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			bad("CreatedAt", value, errors.New("invalid date"))
+		}
+		task.CreatedAt = createdAt
+	}
+	if value, ok := field("CompletedAt"); ok {
+		completedAt, err := parseCompletedAt(value, migratedAt)
+		if err != nil {
+			bad("CompletedAt", value, errors.New("invalid date"))
+		}
+		task.CompletedAt = completedAt
+	}
+	if value, ok := field("Due"); ok {
+		due, err := parseOptionalTime(value)
+		if err != nil {
+			bad("Due", value, errors.New("invalid date"))
+		}
+		task.Due = due
+	}
+	if value, ok := field("Priority"); ok {
+		priority, err := ParsePriority(value)
+		if err != nil {
+			bad("Priority", value, errors.New("invalid priority"))
+		}
+		task.Priority = priority
+	}
+	if value, ok := field("Tags"); ok {
+		task.Tags = splitTags(value)
+	}
+	if value, ok := field("Project"); ok {
+		task.Project = value
+	}
+	if value, ok := field("Recur"); ok && value != "" {
+		if _, err := ParseRecurrence(value); err != nil {
+			bad("Recur", value, errors.New("invalid recurrence"))
+		} else {
+			task.Recur = value
+		}
+	}
+	if value, ok := field("Series"); ok && value != "" {
+		series, err := strconv.Atoi(value)
+		if err != nil || series < 0 {
+			bad("Series", value, errors.New("invalid ID"))
+		}
+		task.Series = max(series, 0)
+	}
+	if value, ok := field("Parent"); ok && value != "" {
+		parent, err := strconv.Atoi(value)
+		if err != nil || parent < 0 {
+			bad("Parent", value, errors.New("invalid ID"))
+		}
+		task.Parent = max(parent, 0)
+	}
+	if value, ok := field("BlockedBy"); ok {
+		blockedBy, err := splitIDs(value)
+		if err != nil {
+			bad("BlockedBy", value, errors.New("invalid ID"))
+		}
+		task.BlockedBy = blockedBy
+	}
+	if value, ok := field("State"); ok && value != "" {
+		if err := checkStateName(value); err != nil || value == StateTodo || value == StateDone {
+			bad("State", value, errors.New("invalid state"))
+		} else {
+			task.State = value
+		}
+	}
+	if value, ok := field("Transitions"); ok {
+		transitions, err := splitTransitions(value)
+		if err != nil {
+			bad("Transitions", value, err)
+		}
+		task.Transitions = transitions
+	}
+	if value, ok := field("TimeLog"); ok {
+		timeLog, err := splitTimeLog(value)
+		if err != nil {
+			bad("TimeLog", value, err)
+		}
+		task.TimeLog = timeLog
+	}
+	if value, ok := field("UpdatedAt"); ok {
+		updatedAt, err := parseOptionalTime(value)
+		if err != nil {
+			bad("UpdatedAt", value, errors.New("invalid date"))
+		}
+		task.UpdatedAt = updatedAt
+	}
+	return task, errs
+}
+
+// readTasks parses every task in the file, locating columns by their header
+// so files written by older versions still load; the next write brings them
+// up to date. Any malformed row fails the whole read with a *RecordError.
+func readTasks(file *os.File) ([]Tasks, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return parseCSV(file, info.ModTime())
+}
+
+// parseCSV reads tasks written in the CSV store's format. Legacy completed
+// rows are dated with migratedAt.
+func parseCSV(r io.Reader, migratedAt time.Time) ([]Tasks, error) {
+	data := csv.NewReader(r)
+	data.FieldsPerRecord = -1
+	records, err := data.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCorruptRecord, err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	layout, err := parseHeader(records[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []Tasks
+	seen := make(map[int]bool)
+	for i, record := range records[1:] {
+		line := i + 2
+		task, errs := layout.parseRecord(record, line, migratedAt)
+		if len(errs) > 0 {
+			return nil, errs[0]
+		}
+		if seen[task.ID] {
+			return nil, &RecordError{Line: line, Field: "ID", Value: strconv.Itoa(task.ID), Err: errDuplicateID}
+		}
+		seen[task.ID] = true
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func taskRecord(t Tasks) []string {
+	return []string{
+		strconv.Itoa(t.ID),
+		t.Description,
+		t.CreatedAt.Format(time.RFC3339),
+		formatOptionalTime(t.CompletedAt),
+		formatOptionalTime(t.Due),
+		t.Priority.String(),
+		joinTags(t.Tags),
+		t.Project,
+		t.Recur,
+		formatOptionalID(t.Series),
+		formatOptionalID(t.Parent),
+		joinIDs(t.BlockedBy),
+		t.State,
+		joinTransitions(t.Transitions),
+		joinTimeLog(t.TimeLog),
+		formatOptionalTime(t.UpdatedAt),
+	}
+}
+
+// parseCompletedAt reads the CompletedAt column, migrating the legacy
+// IsCompleted booleans on the way.
+func parseCompletedAt(value string, migratedAt time.Time) (time.Time, error) {
+	if done, err := strconv.ParseBool(value); err == nil {
+		if done {
+			return migratedAt, nil
+		}
+		return time.Time{}, nil
+	}
+	return parseOptionalTime(value)
+}
+
+// parseOptionalTime reads an empty column as the zero time.
+func parseOptionalTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// splitIDs reads a space separated list of task IDs.
+func splitIDs(value string) ([]int, error) {
+	var ids []int
+	for _, field := range strings.Fields(value) {
+		id, err := strconv.Atoi(field)
+		if err != nil || id < 1 {
+			return nil, fmt.Errorf("invalid ID %q", field)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// joinIDs writes task IDs space separated, like tags.
+func joinIDs(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, " ")
+}
+
+// splitTransitions reads space separated state@time pairs.
+func splitTransitions(value string) ([]Transition, error) {
+	var transitions []Transition
+	for _, field := range strings.Fields(value) {
+		state, at, ok := strings.Cut(field, "@")
+		if !ok || checkStateName(state) != nil {
+			return nil, fmt.Errorf("invalid transition %q", field)
+		}
+		t, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transition %q", field)
+		}
+		transitions = append(transitions, Transition{State: state, At: t})
+	}
+	return transitions, nil
+}
+
+// joinTransitions writes transitions as "in-progress@2026-10-18T09:00:00Z".
+func joinTransitions(transitions []Transition) string {
+	parts := make([]string, len(transitions))
+	for i, t := range transitions {
+		parts[i] = t.State + "@" + t.At.Format(time.RFC3339)
+	}
+	return strings.Join(parts, " ")
+}
+
+// splitTimeLog reads space separated start/end pairs; a running timer has
+// no end.
+func splitTimeLog(value string) ([]TimeEntry, error) {
+	var entries []TimeEntry
+	for _, field := range strings.Fields(value) {
+		start, end, ok := strings.Cut(field, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid time entry %q", field)
+		}
+		startTime, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time entry %q", field)
+		}
+		endTime, err := parseOptionalTime(end)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time entry %q", field)
+		}
+		entries = append(entries, TimeEntry{Start: startTime, End: endTime})
+	}
+	return entries, nil
+}
+
+// joinTimeLog writes entries as "2026-10-18T09:00:00Z/2026-10-18T10:30:00Z".
+func joinTimeLog(entries []TimeEntry) string {
+	parts := make([]string, len(entries))
+	for i, e := range entries {
+		parts[i] = e.Start.Format(time.RFC3339) + "/" + formatOptionalTime(e.End)
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatOptionalID leaves the column empty for tasks outside a series, or
+// without a parent.
+func formatOptionalID(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.Itoa(id)
+}
+
+// formatOptionalTime leaves the column empty for a zero time.
+func formatOptionalTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,335 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// CSVStore keeps tasks in a CSV file. Every access holds a flock on a
+// ".lock" sidecar, shared for reads and exclusive for a whole
+// read-modify-write cycle, and changes go to a temporary file that is
+// renamed over the data file, so neither a crash nor a concurrent reader
+// can observe a half written file.
+type CSVStore struct {
+	path string
+}
+
+func NewCSVStore(path string) *CSVStore {
+	return &CSVStore{path: path}
+}
+
+// LockTimeout is how long to wait for a lock another process holds before
+// failing with ErrLocked. Zero fails at once.
+var LockTimeout = 5 * time.Second
+
+// lockRetry is how often a lock held elsewhere is tried again.
+const lockRetry = 10 * time.Millisecond
+
+// loadFile locks the lock file at path, shared for readers or exclusive for
+// writers, waiting up to LockTimeout. Writers leave their PID in a ".pid"
+// sidecar while they hold the lock, so those kept waiting can tell who
+// holds it. Readers open an existing lock file read-only, so they work in
+// directories they cannot write to.
+func loadFile(path string, exclusive bool) (*os.File, error) {
+	var f *os.File
+	err := os.ErrNotExist
+	if !exclusive {
+		f, err = os.Open(path)
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		f, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	deadline := time.Now().Add(LockTimeout)
+	for {
+		err := syscall.Flock(int(f.Fd()), how|syscall.LOCK_NB)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, syscall.EWOULDBLOCK) {
+			_ = f.Close()
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			// Readers leave no PID; if a shared lock can be had, only
+			// readers are in the way
+			readers := exclusive && syscall.Flock(int(f.Fd()), syscall.LOCK_SH|syscall.LOCK_NB) == nil
+			_ = f.Close()
+			if readers {
+				return nil, lockedError{readers: true}
+			}
+			return nil, lockedError{pid: lockOwner(path)}
+		}
+		time.Sleep(lockRetry)
+	}
+
+	if exclusive {
+		// Only used to word the error others get, so failing to write it
+		// is no reason to give up the lock
+		_ = os.WriteFile(path+".pid", []byte(strconv.Itoa(os.Getpid())+"\n"), 0o644)
+	}
+	return f, nil
+}
+
+func closeFile(f *os.File) error {
+	if lockOwner(f.Name()) == os.Getpid() {
+		_ = os.Remove(f.Name() + ".pid")
+	}
+	syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+	return f.Close()
+}
+
+// lockOwner returns the PID of the writer holding the lock file at path, or
+// zero when none is recorded or the process has gone.
+func lockOwner(path string) int {
+	data, err := os.ReadFile(path + ".pid")
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid < 1 {
+		return 0
+	}
+	// Signal 0 only checks that the process exists; EPERM means it does
+	// but belongs to another user
+	if err := syscall.Kill(pid, 0); err != nil && !errors.Is(err, syscall.EPERM) {
+		return 0
+	}
+	return pid
+}
+
+// The data file itself is replaced on every write, so the lock lives in a
+// sidecar whose inode stays put.
+func (s *CSVStore) lockPath() string {
+	return s.path + ".lock"
+}
+
+func (s *CSVStore) List() ([]Tasks, error) {
+	lock, err := loadFile(s.lockPath(), false)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFile(lock)
+
+	return s.read()
+}
+
+// read loads the data file; a missing file holds no tasks. The caller must
+// hold the lock.
+func (s *CSVStore) read() ([]Tasks, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file for reading: %w", err)
+	}
+	defer file.Close()
+
+	return readTasks(file)
+}
+
+// update runs one locked read-modify-write cycle, replacing the data file
+// with whatever modify returns.
+func (s *CSVStore) update(modify func(tasks []Tasks) ([]Tasks, error)) error {
+	lock, err := loadFile(s.lockPath(), true)
+	if err != nil {
+		return err
+	}
+	defer closeFile(lock)
+
+	tasks, err := s.read()
+	if err != nil {
+		return err
+	}
+	tasks, err = modify(tasks)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(s.path, func(w io.Writer) error {
+		return writeCSV(w, tasks)
+	})
+}
+
+func (s *CSVStore) Get(id int) (Tasks, error) {
+	tasks, err := s.List()
+	if err != nil {
+		return Tasks{}, err
+	}
+	return findTask(tasks, id)
+}
+
+// Create adds the task to the file. A task without an ID gets the next one
+// from the counter kept in the ".id" sidecar, so IDs are never reused after
+// a delete.
+func (s *CSVStore) Create(task Tasks) (Tasks, error) {
+	err := s.update(func(tasks []Tasks) ([]Tasks, error) {
+		nextID, err := s.nextID(tasks)
+		if err != nil {
+			return nil, err
+		}
+		if task.ID == 0 {
+			task.ID = nextID
+		} else if _, err := findTask(tasks, task.ID); err == nil {
+			return nil, errTaskExists(task.ID)
+		}
+
+		// Move the counter on before the task is written; a crash in
+		// between only skips an ID rather than handing it out twice
+		if err := s.saveNextID(max(nextID, task.ID+1)); err != nil {
+			return nil, err
+		}
+		return append(tasks, task), nil
+	})
+	if err != nil {
+		return Tasks{}, err
+	}
+	return task, nil
+}
+
+// nextID returns the next free ID: the stored counter, or one past the
+// highest ID in the file for data written before the counter existed.
+func (s *CSVStore) nextID(tasks []Tasks) (int, error) {
+	next := 1
+	for _, t := range tasks {
+		next = max(next, t.ID+1)
+	}
+
+	data, err := os.ReadFile(s.path + ".id")
+	if errors.Is(err, os.ErrNotExist) {
+		return next, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read ID counter: %w", err)
+	}
+	stored, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid ID counter %q", data)
+	}
+	return max(next, stored), nil
+}
+
+func (s *CSVStore) saveNextID(id int) error {
+	err := writeFileAtomic(s.path+".id", func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, id)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write ID counter: %w", err)
+	}
+	return nil
+}
+
+func (s *CSVStore) Update(task Tasks) error {
+	return s.update(func(tasks []Tasks) ([]Tasks, error) {
+		i := slices.IndexFunc(tasks, func(t Tasks) bool { return t.ID == task.ID })
+		if i < 0 {
+			return nil, notFound(task.ID)
+		}
+		tasks[i] = task
+		return tasks, nil
+	})
+}
+
+func (s *CSVStore) Delete(id int) error {
+	return s.update(func(tasks []Tasks) ([]Tasks, error) {
+		if _, err := findTask(tasks, id); err != nil {
+			return nil, err
+		}
+
+		// Filter the task
+		return slices.DeleteFunc(tasks, func(t Tasks) bool { return t.ID == id }), nil
+	})
+}
+
+// Batch holds the lock for the whole of fn, which works on the tasks in
+// memory; the file and ID counter are only written once fn succeeds.
+func (s *CSVStore) Batch(fn func(tx Store) error) error {
+	return s.update(func(tasks []Tasks) ([]Tasks, error) {
+		nextID, err := s.nextID(tasks)
+		if err != nil {
+			return nil, err
+		}
+		tx := NewMemoryStore(tasks...)
+		tx.nextID = nextID
+		if err := fn(tx); err != nil {
+			return nil, err
+		}
+		if tx.nextID != nextID {
+			if err := s.saveNextID(tx.nextID); err != nil {
+				return nil, err
+			}
+		}
+		return tx.tasks, nil
+	})
+}
+
+func (s *CSVStore) Close() error {
+	return nil
+}
+
+// writeFileAtomic replaces path with the output of write. The data goes to a
+// temporary file in the same directory which is synced to disk and then
+// renamed over path; on any error the original file is left untouched.
+func writeFileAtomic(path string, write func(w io.Writer) error) (err error) {
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+
+	tmp, err := os.CreateTemp(dir, base+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	// Keep the permissions of the file being replaced
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		return err
+	}
+
+	if err := write(tmp); err != nil {
+		return fmt.Errorf("failed to write %s: %w", base, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to sync %s: %w", base, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", base, err)
+	}
+
+	// Persist the rename itself
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+	return nil
+}
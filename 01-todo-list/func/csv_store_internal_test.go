@@ -0,0 +1,221 @@
+package tasks
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWriteFileAtomicKeepsOriginalOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "db.csv")
+	original := []byte("ID,Description\n1,keep me\n")
+	if err := os.WriteFile(path, original, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fail halfway through writing the replacement
+	errDiskFull := errors.New("disk full")
+	err := writeFileAtomic(path, func(w io.Writer) error {
+		if _, err := io.WriteString(w, "ID,Descr"); err != nil {
+			return err
+		}
+		return errDiskFull
+	})
+	if !errors.Is(err, errDiskFull) {
+		t.Fatalf("got error %v, want %v", err, errDiskFull)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, original) {
+		t.Fatalf("file changed to %q", got)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("temporary file left behind: %v", entries)
+	}
+}
+
+func TestCSVStoreReadersNeverSeePartialWrites(t *testing.T) {
+	s := NewCSVStore(filepath.Join(t.TempDir(), "db.csv"))
+	const count = 20
+	for range count {
+		if _, err := s.Create(Tasks{Description: "task", CreatedAt: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			tasks, err := s.List()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if len(tasks) != count {
+				t.Errorf("reader saw %d tasks, want %d", len(tasks), count)
+				return
+			}
+		}
+	}()
+
+	for i := range 50 {
+		task, err := s.Get(i%count + 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		task.CompletedAt = time.Now()
+		if err := s.Update(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestLockTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { LockTimeout = timeout }(LockTimeout)
+	LockTimeout = 50 * time.Millisecond
+	s := NewCSVStore(filepath.Join(t.TempDir(), "db.csv"))
+
+	// Readers share the lock
+	reader, err := loadFile(s.lockPath(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.List(); err != nil {
+		t.Fatalf("list beside a reader: %v", err)
+	}
+	_, err = s.Create(Tasks{Description: "task", CreatedAt: time.Now()})
+	if !errors.Is(err, ErrLocked) || err.Error() != "database is locked by other processes reading it" {
+		t.Fatalf("create beside a reader: got %v, want ErrLocked", err)
+	}
+	closeFile(reader)
+
+	// Writers keep everyone out and say who they are
+	writer, err := loadFile(s.lockPath(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.List()
+	if want := fmt.Sprintf("database is locked by PID %d", os.Getpid()); !errors.Is(err, ErrLocked) || err.Error() != want {
+		t.Fatalf("list beside a writer: got %v, want %q", err, want)
+	}
+	closeFile(writer)
+	if _, err := os.Stat(s.lockPath() + ".pid"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("lock owner left behind: %v", err)
+	}
+	if _, err := s.Create(Tasks{Description: "task", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("create once unlocked: %v", err)
+	}
+}
+
+func TestListReadOnly(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only files")
+	}
+	dir := t.TempDir()
+	s := NewCSVStore(filepath.Join(dir, "db.csv"))
+	if _, err := s.Create(Tasks{Description: "task", CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{s.lockPath(), dir} {
+		if err := os.Chmod(path, 0o555); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer os.Chmod(dir, 0o755)
+
+	if list, err := s.List(); err != nil || len(list) != 1 {
+		t.Fatalf("list without write access: got %v, %v", list, err)
+	}
+}
+
+// TestParallelAdds starts many processes adding tasks to the same file at
+// once; every task must be stored exactly once.
+func TestParallelAdds(t *testing.T) {
+	if testing.Short() {
+		t.Skip("starts child processes")
+	}
+	path := filepath.Join(t.TempDir(), "db.csv")
+	const procs, adds = 16, 10
+
+	var wg sync.WaitGroup
+	for p := range procs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cmd := exec.Command(os.Args[0], "-test.run=^TestParallelAddsHelper$")
+			cmd.Env = append(os.Environ(), "TASKS_HELPER_FILE="+path, fmt.Sprintf("TASKS_HELPER_PREFIX=p%d", p))
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("process %d: %v\n%s", p, err, out)
+			}
+		}()
+	}
+	wg.Wait()
+
+	tasks, err := NewCSVStore(path).List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != procs*adds {
+		t.Errorf("stored %d tasks, want %d", len(tasks), procs*adds)
+	}
+	ids := make(map[int]bool)
+	descriptions := make(map[string]bool)
+	for _, task := range tasks {
+		if ids[task.ID] || descriptions[task.Description] {
+			t.Errorf("task %d %q stored twice", task.ID, task.Description)
+		}
+		ids[task.ID] = true
+		descriptions[task.Description] = true
+	}
+	for p := range procs {
+		for i := range adds {
+			if !descriptions[fmt.Sprintf("p%d-%d", p, i)] {
+				t.Errorf("task p%d-%d lost", p, i)
+			}
+		}
+	}
+}
+
+func TestParallelAddsHelper(t *testing.T) {
+	path := os.Getenv("TASKS_HELPER_FILE")
+	if path == "" {
+		t.Skip("run by TestParallelAdds")
+	}
+	LockTimeout = time.Minute
+	s := NewCSVStore(path)
+	for i := range 10 {
+		if _, err := s.Create(Tasks{Description: fmt.Sprintf("%s-%d", os.Getenv("TASKS_HELPER_PREFIX"), i), CreatedAt: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+		// Reads in between take the shared lock
+		if _, err := s.List(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
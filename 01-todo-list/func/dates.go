@@ -0,0 +1,175 @@
+package tasks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mergestat/timediff"
+)
+
+// dateLayouts are the absolute formats accepted for due dates.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseDue turns a due date such as "2026-11-01", "tomorrow", "next friday",
+// "in 3 days" or "2w" into a time relative to now. Dates without a time of
+// day are due at the end of that day.
+func ParseDue(s string, now time.Time) (time.Time, error) {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return time.Time{}, fmt.Errorf("empty due date")
+	}
+
+	// The layouts spell out the T and Z of RFC 3339 in capitals, so only
+	// the relative forms below are lowercased
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, trimmed, now.Location()); err == nil {
+			if layout == "2006-01-02" {
+				return endOfDay(t), nil
+			}
+			return t, nil
+		}
+	}
+
+	value := strings.ToLower(trimmed)
+	switch value {
+	case "today", "tonight":
+		return endOfDay(now), nil
+	case "tomorrow":
+		return endOfDay(now.AddDate(0, 0, 1)), nil
+	case "next week":
+		return endOfDay(now.AddDate(0, 0, 7)), nil
+	case "next month":
+		return endOfDay(now.AddDate(0, 1, 0)), nil
+	}
+
+	// "friday" is the coming friday, today included; "next friday" is the
+	// first one after today.
+	if name, ok := strings.CutPrefix(value, "next "); ok {
+		if day, ok := parseWeekday(name); ok {
+			return endOfDay(nextWeekday(now.AddDate(0, 0, 1), day)), nil
+		}
+	}
+	if day, ok := parseWeekday(value); ok {
+		return endOfDay(nextWeekday(now, day)), nil
+	}
+
+	if d, err := ParseDuration(strings.TrimPrefix(value, "in ")); err == nil {
+		if d%(24*time.Hour) == 0 {
+			return endOfDay(now.Add(d)), nil
+		}
+		return now.Add(d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("cannot understand due date %q", s)
+}
+
+// ParseDuration extends time.ParseDuration with days and weeks ("3d", "2w",
+// "1w2d", "1d12h") and spelled out units ("3 days", "1 week").
+func ParseDuration(s string) (time.Duration, error) {
+	value := strings.ToLower(strings.TrimSpace(s))
+	if value == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+	if amount, unit, ok := strings.Cut(value, " "); ok {
+		n, err := strconv.Atoi(amount)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		switch strings.TrimSuffix(unit, "s") {
+		case "minute":
+			return time.Duration(n) * time.Minute, nil
+		case "hour":
+			return time.Duration(n) * time.Hour, nil
+		case "day":
+			return time.Duration(n) * 24 * time.Hour, nil
+		case "week":
+			return time.Duration(n) * 7 * 24 * time.Hour, nil
+		}
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	var total time.Duration
+	for _, unit := range []struct {
+		suffix string
+		size   time.Duration
+	}{{"w", 7 * 24 * time.Hour}, {"d", 24 * time.Hour}} {
+		if before, after, ok := strings.Cut(value, unit.suffix); ok {
+			n, err := strconv.Atoi(before)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			total += time.Duration(n) * unit.size
+			value = after
+		}
+	}
+	if value == "" {
+		return total, nil
+	}
+
+	rest, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return total + rest, nil
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || name == full[:3] {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// nextWeekday returns the first date on or after from falling on day.
+func nextWeekday(from time.Time, day time.Weekday) time.Time {
+	return from.AddDate(0, 0, (int(day)-int(from.Weekday())+7)%7)
+}
+
+func endOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 23, 59, 59, 0, t.Location())
+}
+
+// formatDue shows the due date with how far away it is.
+func formatDue(due time.Time) string {
+	if due.IsZero() {
+		return "-"
+	}
+	if !due.Equal(endOfDay(due)) {
+		return fmt.Sprintf("%s (%s)", due.Format("2006-01-02 15:04"), timeDiff(due))
+	}
+	return fmt.Sprintf("%s (%s)", due.Format("2006-01-02"), daysUntil(due, time.Now()))
+}
+
+// daysUntil says how far away a date without a time of day is in calendar
+// days. Counted in hours, the end of tomorrow is two days away in the
+// morning.
+func daysUntil(due, now time.Time) string {
+	y, m, d := now.In(due.Location()).Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	y, m, d = due.Date()
+	days := int(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Sub(today).Hours() / 24)
+	switch {
+	case days == 0:
+		return "today"
+	case days == 1:
+		return "tomorrow"
+	case days == -1:
+		return "yesterday"
+	case days > 1 && days <= 25:
+		return fmt.Sprintf("in %d days", days)
+	case days < -1 && days >= -25:
+		return fmt.Sprintf("%d days ago", -days)
+	}
+	return timediff.TimeDiff(due, timediff.WithStartTime(now))
+}
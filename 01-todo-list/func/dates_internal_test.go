@@ -0,0 +1,26 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDaysUntil(t *testing.T) {
+	now := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		due  time.Time
+		want string
+	}{
+		{endOfDay(now), "today"},
+		{endOfDay(now.AddDate(0, 0, 1)), "tomorrow"},
+		{endOfDay(now.AddDate(0, 0, 2)), "in 2 days"},
+		{endOfDay(now.AddDate(0, 0, -1)), "yesterday"},
+		{endOfDay(now.AddDate(0, 0, -3)), "3 days ago"},
+		{endOfDay(now.AddDate(0, 0, 58)), "in 2 months"},
+	}
+	for _, tt := range tests {
+		if got := daysUntil(tt.due, now); got != tt.want {
+			t.Errorf("daysUntil(%s) = %q, want %q", tt.due.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
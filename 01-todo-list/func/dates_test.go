@@ -0,0 +1,72 @@
+package tasks_test
+
+import (
+	"testing"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+)
+
+func TestParseDue(t *testing.T) {
+	// Wednesday afternoon
+	now := time.Date(2026, 10, 14, 15, 0, 0, 0, time.UTC)
+	endOf := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 23, 59, 59, 0, time.UTC)
+	}
+
+	cases := map[string]time.Time{
+		"2026-11-01":                endOf(2026, 11, 1),
+		"2026-11-01 09:30":          time.Date(2026, 11, 1, 9, 30, 0, 0, time.UTC),
+		"2026-11-01T09:30":          time.Date(2026, 11, 1, 9, 30, 0, 0, time.UTC),
+		"2026-11-01T10:00:00Z":      time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC),
+		"2026-11-01T10:00:00+02:00": time.Date(2026, 11, 1, 8, 0, 0, 0, time.UTC),
+		"today":                     endOf(2026, 10, 14),
+		"tomorrow":                  endOf(2026, 10, 15),
+		"friday":                    endOf(2026, 10, 16),
+		"wed":                       endOf(2026, 10, 14),
+		"next wednesday":            endOf(2026, 10, 21),
+		"Next Friday":               endOf(2026, 10, 16),
+		"in 3 days":                 endOf(2026, 10, 17),
+		"1w":                        endOf(2026, 10, 21),
+		"in 4 hours":                time.Date(2026, 10, 14, 19, 0, 0, 0, time.UTC),
+		"90m":                       time.Date(2026, 10, 14, 16, 30, 0, 0, time.UTC),
+	}
+	for input, want := range cases {
+		got, err := tasks.ParseDue(input, now)
+		if err != nil {
+			t.Errorf("ParseDue(%q): %v", input, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("ParseDue(%q) = %v, want %v", input, got, want)
+		}
+	}
+
+	for _, input := range []string{"", "someday", "next blursday", "in x days"} {
+		if _, err := tasks.ParseDue(input, now); err == nil {
+			t.Errorf("ParseDue(%q): expected error", input)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"3d":      72 * time.Hour,
+		"2w":      14 * 24 * time.Hour,
+		"1w2d":    9 * 24 * time.Hour,
+		"1d12h":   36 * time.Hour,
+		"1h30m":   90 * time.Minute,
+		"30 days": 30 * 24 * time.Hour,
+		"1 week":  7 * 24 * time.Hour,
+	}
+	for input, want := range cases {
+		got, err := tasks.ParseDuration(input)
+		if err != nil {
+			t.Errorf("ParseDuration(%q): %v", input, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package tasks
+
+import (
+	"fmt"
+	"slices"
+)
+
+// openSubtasksError is returned when completing a task whose subtasks are
+// still open.
+type openSubtasksError struct {
+	id       int
+	children []int
+}
+
+func (e openSubtasksError) Error() string {
+	return fmt.Sprintf("task %d has open subtasks %s, complete them first or use --force", e.id, formatIDs(e.children))
+}
+
+// checkLinks verifies that the parent and blockers of t exist among tasks
+// and that neither link closes a cycle.
+func checkLinks(tasks []Tasks, t Tasks) error {
+	byID := make(map[int]Tasks, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	if _, ok := byID[t.Parent]; t.Parent != 0 && !ok {
+		return fmt.Errorf("parent %w", notFound(t.Parent))
+	}
+	seen := make(map[int]bool)
+	for id := t.Parent; id != 0 && !seen[id]; id = byID[id].Parent {
+		if id == t.ID {
+			return fmt.Errorf("task %d cannot be a subtask of itself or its own subtasks", t.ID)
+		}
+		seen[id] = true
+	}
+
+	for _, blocker := range t.BlockedBy {
+		if blocker == t.ID {
+			return fmt.Errorf("task %d cannot block itself", t.ID)
+		}
+		if _, ok := byID[blocker]; !ok {
+			return fmt.Errorf("blocking %w", notFound(blocker))
+		}
+		if t.ID != 0 && blocks(byID, t.ID, []int{blocker}) {
+			return fmt.Errorf("task %d cannot wait for task %d, which already waits for it", t.ID, blocker)
+		}
+	}
+	return nil
+}
+
+// blocks reports whether id is reachable through the blocked-by links of
+// the given tasks.
+func blocks(byID map[int]Tasks, id int, from []int) bool {
+	seen := make(map[int]bool)
+	for len(from) > 0 {
+		next := from[len(from)-1]
+		from = from[:len(from)-1]
+		if next == id {
+			return true
+		}
+		if seen[next] {
+			continue
+		}
+		seen[next] = true
+		from = append(from, byID[next].BlockedBy...)
+	}
+	return false
+}
+
+// openChildren returns the IDs of the open subtasks of id.
+func openChildren(tasks []Tasks, id int) []int {
+	var children []int
+	for _, task := range tasks {
+		if task.Parent == id && !task.Completed() {
+			children = append(children, task.ID)
+		}
+	}
+	return children
+}
+
+// checkSubtasks refuses to complete tasks with open subtasks, unless those
+// subtasks are being completed along with them.
+func checkSubtasks(s Store, selected []Tasks) error {
+	tasks, err := s.List()
+	if err != nil {
+		return err
+	}
+	completing := make(map[int]bool, len(selected))
+	for _, task := range selected {
+		completing[task.ID] = true
+	}
+	for _, task := range selected {
+		if task.Completed() {
+			continue
+		}
+		children := slices.DeleteFunc(openChildren(tasks, task.ID), func(id int) bool { return completing[id] })
+		if len(children) > 0 {
+			return openSubtasksError{id: task.ID, children: children}
+		}
+	}
+	return nil
+}
+
+// unlink removes references to a deleted task: its subtasks move up to its
+// parent and it no longer blocks anything.
+func unlink(s Store, deleted Tasks) error {
+	tasks, err := s.List()
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		changed := false
+		if task.Parent == deleted.ID {
+			task.Parent = deleted.Parent
+			changed = true
+		}
+		if slices.Contains(task.BlockedBy, deleted.ID) {
+			task.BlockedBy = slices.DeleteFunc(task.BlockedBy, func(id int) bool { return id == deleted.ID })
+			changed = true
+		}
+		if changed {
+			if err := s.Update(task); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// actionable tells which open tasks can be worked on now: those without
+// open subtasks and whose blockers are all done. Blockers that no longer
+// exist, such as archived tasks, count as done.
+func actionable(tasks []Tasks) func(Tasks) bool {
+	open := make(map[int]bool)
+	hasOpenChildren := make(map[int]bool)
+	for _, task := range tasks {
+		if !task.Completed() {
+			open[task.ID] = true
+			if task.Parent != 0 {
+				hasOpenChildren[task.Parent] = true
+			}
+		}
+	}
+	return func(t Tasks) bool {
+		if t.Completed() || hasOpenChildren[t.ID] {
+			return false
+		}
+		return !slices.ContainsFunc(t.BlockedBy, func(id int) bool { return open[id] })
+	}
+}
+
+// treeOrder sorts tasks depth first under their parents, keeping the order
+// of siblings, and returns the prefix drawing each task's place in the
+// tree. Tasks whose parent is not among them are roots.
+func treeOrder(tasks []Tasks) ([]Tasks, map[int]string) {
+	present := make(map[int]bool, len(tasks))
+	for _, task := range tasks {
+		present[task.ID] = true
+	}
+	children := make(map[int][]Tasks)
+	var roots []Tasks
+	for _, task := range tasks {
+		if task.Parent != 0 && present[task.Parent] && task.Parent != task.ID {
+			children[task.Parent] = append(children[task.Parent], task)
+		} else {
+			roots = append(roots, task)
+		}
+	}
+
+	ordered := make([]Tasks, 0, len(tasks))
+	prefix := make(map[int]string, len(tasks))
+	var walk func(level []Tasks, indent string, top bool)
+	walk = func(level []Tasks, indent string, top bool) {
+		for i, task := range level {
+			last := i == len(level)-1
+			branch, next := "├─ ", "│  "
+			if last {
+				branch, next = "└─ ", "   "
+			}
+			if top {
+				branch, next = "", ""
+			}
+			prefix[task.ID] = indent + branch
+			ordered = append(ordered, task)
+			walk(children[task.ID], indent+next, false)
+		}
+	}
+	walk(roots, "", true)
+	return ordered, prefix
+}
@@ -0,0 +1,181 @@
+package tasks_test
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+)
+
+// newProject stores a small plan: 2 and 3 are subtasks of 1, 3 waits for 2
+// and 4 is a subtask of 3.
+func newProject() *tasks.MemoryStore {
+	now := time.Now()
+	return tasks.NewMemoryStore(
+		tasks.Tasks{ID: 1, Description: "Plan party", CreatedAt: now},
+		tasks.Tasks{ID: 2, Description: "Book venue", CreatedAt: now, Parent: 1},
+		tasks.Tasks{ID: 3, Description: "Send invites", CreatedAt: now, Parent: 1, BlockedBy: []int{2}},
+		tasks.Tasks{ID: 4, Description: "Buy cake", CreatedAt: now, Parent: 3},
+		tasks.Tasks{ID: 5, Description: "Water plants", CreatedAt: now},
+	)
+}
+
+func TestLinksRejectCycles(t *testing.T) {
+	s := newProject()
+	if _, err := tasks.AddNewTask(s, tasks.Tasks{Description: "Orphan", Parent: 9}); !errors.Is(err, tasks.ErrNotFound) {
+		t.Errorf("unknown parent: got %v, want not found", err)
+	}
+	if _, err := tasks.AddNewTask(s, tasks.Tasks{Description: "Wait", BlockedBy: []int{9}}); !errors.Is(err, tasks.ErrNotFound) {
+		t.Errorf("unknown blocker: got %v, want not found", err)
+	}
+
+	parent := 4
+	if _, err := tasks.EditTask(s, 1, tasks.TaskEdit{Parent: &parent}); err == nil {
+		t.Error("moving a task under its own subtask should fail")
+	}
+	if _, err := tasks.EditTask(s, 2, tasks.TaskEdit{AddBlockers: []int{3}}); err == nil {
+		t.Error("blocking a task on one that waits for it should fail")
+	}
+	if _, err := tasks.EditTask(s, 5, tasks.TaskEdit{AddBlockers: []int{5}}); err == nil {
+		t.Error("a task blocking itself should fail")
+	}
+
+	task, err := tasks.EditTask(s, 5, tasks.TaskEdit{Parent: &parent, AddBlockers: []int{2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Parent != 4 || !slices.Equal(task.BlockedBy, []int{2}) {
+		t.Errorf("got parent %d blocked by %v, want 4 and [2]", task.Parent, task.BlockedBy)
+	}
+}
+
+func TestCompleteParentWithOpenSubtasks(t *testing.T) {
+	s := newProject()
+	if _, err := tasks.CompleteTask(s, 1); err == nil {
+		t.Fatal("completing a task with open subtasks should fail")
+	}
+	if _, _, err := tasks.CompleteTasks(s, tasks.ListOptions{IDs: []int{3}}, true, false); err == nil {
+		t.Error("dry run should refuse open subtasks too")
+	}
+
+	// Completing the subtasks along with the parent is fine
+	done, _, err := tasks.CompleteTasks(s, tasks.ListOptions{IDs: []int{3, 4}}, false, false)
+	if err != nil || len(done) != 2 {
+		t.Fatalf("got %v, %v; want 2 tasks completed", done, err)
+	}
+	if _, _, err := tasks.CompleteTasks(s, tasks.ListOptions{IDs: []int{1}}, false, true); err != nil {
+		t.Fatalf("force: %v", err)
+	}
+	if task, _ := s.Get(2); task.Completed() {
+		t.Error("forcing the parent should leave its subtasks alone")
+	}
+}
+
+func TestDeleteUnlinks(t *testing.T) {
+	s := newProject()
+	if _, err := tasks.DeleteTask(s, 3); err != nil {
+		t.Fatal(err)
+	}
+	if task, _ := s.Get(4); task.Parent != 1 {
+		t.Errorf("subtask of deleted task has parent %d, want 1", task.Parent)
+	}
+
+	if _, err := tasks.DeleteTasks(s, tasks.ListOptions{IDs: []int{2}}, false); err != nil {
+		t.Fatal(err)
+	}
+	all, _ := s.List()
+	for _, task := range all {
+		if slices.Contains(task.BlockedBy, 2) {
+			t.Errorf("task %d still blocked by deleted task 2", task.ID)
+		}
+	}
+}
+
+func TestActionable(t *testing.T) {
+	s := newProject()
+	ids := func() []int {
+		list, err := tasks.ListTasks(s, tasks.ListOptions{Status: tasks.StatusOpen, Actionable: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []int
+		for _, task := range list.All() {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	if got := ids(); !slices.Equal(got, []int{2, 4, 5}) {
+		t.Errorf("got %v, want [2 4 5]", got)
+	}
+	if _, err := tasks.CompleteTask(s, 4); err != nil {
+		t.Fatal(err)
+	}
+	// 3 still waits for 2
+	if got := ids(); !slices.Equal(got, []int{2, 5}) {
+		t.Errorf("got %v, want [2 5]", got)
+	}
+	if _, err := tasks.CompleteTask(s, 2); err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(); !slices.Equal(got, []int{3, 5}) {
+		t.Errorf("got %v, want [3 5]", got)
+	}
+}
+
+func TestListTree(t *testing.T) {
+	s := newProject()
+	list, err := tasks.ListTasks(s, tasks.ListOptions{Status: tasks.StatusOpen, Tree: true, SortBy: "-id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := (tasks.Printer{Out: &out, Format: tasks.OutputTable}).PrintTaskList(list); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, line := range strings.Split(out.String(), "\n")[1:] {
+		if fields := strings.Split(line, "|"); len(fields) > 1 {
+			got = append(got, strings.TrimSpace(fields[1]))
+		}
+	}
+	want := []string{
+		"Water plants",
+		"Plan party",
+		"├─ Send invites",
+		"│  └─ Buy cake",
+		"└─ Book venue",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLinksPersist(t *testing.T) {
+	for name, open := range openStores(t) {
+		t.Run(name, func(t *testing.T) {
+			s := open()
+			mustCreate(t, s, "one")
+			mustCreate(t, s, "two")
+			if _, err := tasks.AddNewTask(s, tasks.Tasks{Description: "three", Parent: 1, BlockedBy: []int{1, 2}}); err != nil {
+				t.Fatal(err)
+			}
+			s.Close()
+
+			s = open()
+			defer s.Close()
+			task, err := s.Get(3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if task.Parent != 1 || !slices.Equal(task.BlockedBy, []int{1, 2}) {
+				t.Errorf("got parent %d blocked by %v, want 1 and [1 2]", task.Parent, task.BlockedBy)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package tasks
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Problem is one issue found in a data file by Doctor.
+type Problem struct {
+	Line    int    `json:"line" yaml:"line"`
+	Field   string `json:"field" yaml:"field"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// DoctorReport is the result of checking a data file.
+type DoctorReport struct {
+	Path     string    `json:"path" yaml:"path"`
+	Tasks    int       `json:"tasks" yaml:"tasks"` // tasks kept after repair
+	Problems []Problem `json:"problems" yaml:"problems"`
+	Backup   string    `json:"backup,omitempty" yaml:"backup,omitempty"` // set once a repaired file was written
+}
+
+// Doctor scans the data file for rows that List would reject: missing
+// headers, bad IDs and duplicates, invalid dates and empty descriptions.
+// With repair set and problems found, the original is copied next to the
+// file and a repaired version written in its place: broken IDs get new
+// ones, a bad CreatedAt becomes the file's modification time, other bad
+// fields are cleared and rows without a description are dropped.
+func (s *CSVStore) Doctor(repair bool) (DoctorReport, error) {
+	report := DoctorReport{Path: s.path}
+
+	lock, err := loadFile(s.lockPath(), repair)
+	if err != nil {
+		return report, err
+	}
+	defer closeFile(lock)
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return report, nil
+	}
+	if err != nil {
+		return report, fmt.Errorf("failed to read file: %w", err)
+	}
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return report, err
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return report, fmt.Errorf("%w: %w", ErrCorruptRecord, err)
+	}
+	if len(records) == 0 {
+		return report, nil
+	}
+
+	problem := func(e *RecordError) {
+		message := e.Err.Error()
+		if e.Value != "" {
+			message = fmt.Sprintf("%s %q", message, e.Value)
+		}
+		report.Problems = append(report.Problems, Problem{Line: e.Line, Field: e.Field, Message: message})
+	}
+
+	// Without a usable header the columns are assumed to be in the current
+	// order. A first row starting with a number is data, not a broken header.
+	layout, err := parseHeader(records[0])
+	first := 1
+	if err != nil {
+		var recordErr *RecordError
+		if !errors.As(err, &recordErr) {
+			return report, err
+		}
+		problem(recordErr)
+		layout = defaultLayout()
+		if _, err := strconv.Atoi(records[0][0]); err == nil {
+			first = 0
+		}
+	}
+
+	var (
+		tasks    []Tasks
+		renumber []int // indexes into tasks needing a new ID
+		highest  int   // dropped rows included, so their IDs stay unused
+	)
+	seen := make(map[int]bool)
+	for i := first; i < len(records); i++ {
+		line := i + 1
+		task, errs := layout.parseRecord(records[i], line, info.ModTime())
+		for _, e := range errs {
+			problem(e)
+		}
+		if task.ID > 0 && seen[task.ID] {
+			problem(&RecordError{Line: line, Field: "ID", Value: strconv.Itoa(task.ID), Err: errDuplicateID})
+			task.ID = 0
+		}
+		seen[task.ID] = true
+		highest = max(highest, task.ID)
+
+		if task.Description == "" {
+			continue
+		}
+		if task.CreatedAt.IsZero() {
+			task.CreatedAt = info.ModTime()
+		}
+		if task.ID == 0 {
+			renumber = append(renumber, len(tasks))
+		}
+		tasks = append(tasks, task)
+	}
+	report.Tasks = len(tasks)
+
+	if !repair || len(report.Problems) == 0 {
+		return report, nil
+	}
+
+	if len(renumber) > 0 {
+		next, err := s.nextID(tasks)
+		if err != nil {
+			return report, err
+		}
+		next = max(next, highest+1)
+		for _, i := range renumber {
+			tasks[i].ID = next
+			next++
+		}
+		if err := s.saveNextID(next); err != nil {
+			return report, err
+		}
+	}
+
+	backup := fmt.Sprintf("%s.bak-%s", s.path, time.Now().Format("20060102-150405"))
+	err = writeFileAtomic(backup, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+	if err != nil {
+		return report, fmt.Errorf("failed to back up file: %w", err)
+	}
+	err = writeFileAtomic(s.path, func(w io.Writer) error {
+		return writeCSV(w, tasks)
+	})
+	if err != nil {
+		return report, err
+	}
+	report.Backup = backup
+	return report, nil
+}
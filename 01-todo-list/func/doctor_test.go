@@ -0,0 +1,124 @@
+package tasks_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+)
+
+const brokenCSV = `ID,Description,CreatedAt,CompletedAt,Due
+1,first,2026-01-01T00:00:00Z,,
+1,duplicate,2026-01-01T00:00:00Z,,
+2,bad dates,yesterday,,2026-13-01
+3,,2026-01-01T00:00:00Z,,
+`
+
+func writeData(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "db.csv")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCSVStoreRecordError(t *testing.T) {
+	for _, tc := range []struct {
+		name, data string
+		line       int
+		field      string
+	}{
+		{"missing header", "1,a,2026-01-01T00:00:00Z\n", 1, "header"},
+		{"invalid date", "ID,Description,CreatedAt,Due\n1,a,2026-01-01T00:00:00Z,\n2,b,2026-01-01T00:00:00Z,soon\n", 3, "Due"},
+		{"duplicate", "ID,Description,CreatedAt\n4,a,2026-01-01T00:00:00Z\n4,b,2026-01-01T00:00:00Z\n", 3, "ID"},
+		{"short row", "ID,Description,CreatedAt\n1,a\n", 2, "CreatedAt"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tasks.NewCSVStore(writeData(t, tc.data)).List()
+			var recordErr *tasks.RecordError
+			if !errors.As(err, &recordErr) {
+				t.Fatalf("got %v, want a RecordError", err)
+			}
+			if recordErr.Line != tc.line || recordErr.Field != tc.field {
+				t.Fatalf("got line %d field %s, want line %d field %s", recordErr.Line, recordErr.Field, tc.line, tc.field)
+			}
+			if !errors.Is(err, tasks.ErrCorruptRecord) {
+				t.Fatalf("%v does not match ErrCorruptRecord", err)
+			}
+		})
+	}
+}
+
+func TestDoctorReportsWithoutChanging(t *testing.T) {
+	path := writeData(t, brokenCSV)
+	report, err := tasks.NewCSVStore(path).Doctor(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []tasks.Problem{
+		{Line: 3, Field: "ID"},
+		{Line: 4, Field: "CreatedAt"},
+		{Line: 4, Field: "Due"},
+		{Line: 5, Field: "Description"},
+	}
+	if len(report.Problems) != len(want) {
+		t.Fatalf("got problems %+v, want %d", report.Problems, len(want))
+	}
+	for i, p := range report.Problems {
+		if p.Line != want[i].Line || p.Field != want[i].Field {
+			t.Errorf("problem %d: got line %d %s, want line %d %s", i, p.Line, p.Field, want[i].Line, want[i].Field)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != brokenCSV || report.Backup != "" {
+		t.Fatal("file changed without repair")
+	}
+}
+
+func TestDoctorRepair(t *testing.T) {
+	path := writeData(t, brokenCSV)
+	s := tasks.NewCSVStore(path)
+	report, err := s.Doctor(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backup, err := os.ReadFile(report.Backup)
+	if err != nil {
+		t.Fatalf("no backup: %v", err)
+	}
+	if string(backup) != brokenCSV {
+		t.Fatal("backup differs from the original")
+	}
+
+	list, err := s.List()
+	if err != nil {
+		t.Fatalf("repaired file still fails: %v", err)
+	}
+	got := make(map[string]tasks.Tasks)
+	for _, task := range list {
+		got[task.Description] = task
+	}
+	if len(list) != 3 || report.Tasks != 3 {
+		t.Fatalf("got %d tasks, want 3", len(list))
+	}
+	// The duplicate must not take the ID of the dropped row
+	if got["first"].ID != 1 || got["duplicate"].ID != 4 {
+		t.Fatalf("got IDs %d and %d, want 1 and 4", got["first"].ID, got["duplicate"].ID)
+	}
+	if bad := got["bad dates"]; bad.CreatedAt.IsZero() || !bad.Due.IsZero() {
+		t.Fatalf("bad dates not repaired: %+v", bad)
+	}
+
+	if report, err := s.Doctor(false); err != nil || len(report.Problems) != 0 {
+		t.Fatalf("after repair: %v %+v", err, report.Problems)
+	}
+}
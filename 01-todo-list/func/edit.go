@@ -0,0 +1,267 @@
+package tasks
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TaskEdit lists the changes EditTask makes to a task; nil fields are left
+// as they are.
+type TaskEdit struct {
+	Description    *string
+	Due            *time.Time // the zero time removes the due date
+	Priority       *Priority
+	Project        *string
+	Recur          *string  // an empty rule stops the task recurring
+	Parent         *int     // zero moves the task to the top level
+	Tags           []string // replaces all tags when not nil
+	AddTags        []string
+	RemoveTags     []string
+	AddBlockers    []int
+	RemoveBlockers []int
+}
+
+// Empty reports whether the edit changes nothing.
+func (e TaskEdit) Empty() bool {
+	return e.Description == nil && e.Due == nil && e.Priority == nil && e.Project == nil && e.Recur == nil &&
+		e.Parent == nil && e.Tags == nil && len(e.AddTags) == 0 && len(e.RemoveTags) == 0 &&
+		len(e.AddBlockers) == 0 && len(e.RemoveBlockers) == 0
+}
+
+// EditTask applies the edit to a stored task, keeping its ID and CreatedAt.
+// As with AddNewTask, +tag and @project words in a new description are
+// moved into Tags and Project. New parents and blockers must exist and may
+// not make the task depend on itself.
+func EditTask(s Store, id int, edit TaskEdit) (Tasks, error) {
+	var task Tasks
+	err := s.Batch(func(tx Store) error {
+		var err error
+		if task, err = tx.Get(id); err != nil {
+			return err
+		}
+		if task, err = edit.apply(task); err != nil {
+			return err
+		}
+		if edit.Parent != nil || len(edit.AddBlockers) > 0 {
+			tasks, err := tx.List()
+			if err != nil {
+				return err
+			}
+			if err := checkLinks(tasks, task); err != nil {
+				return err
+			}
+		}
+		return tx.Update(task)
+	})
+	if err != nil {
+		return Tasks{}, err
+	}
+	return task, nil
+}
+
+func (e TaskEdit) apply(task Tasks) (Tasks, error) {
+	if e.Tags != nil {
+		task.Tags = nil
+		for _, tag := range e.Tags {
+			var err error
+			if task.Tags, err = addTag(task.Tags, tag); err != nil {
+				return Tasks{}, err
+			}
+		}
+	}
+	if e.Description != nil {
+		description, tags, project := ParseDescription(*e.Description)
+		if description == "" {
+			return Tasks{}, errors.New("task description is empty")
+		}
+		task.Description = description
+		for _, tag := range tags {
+			task.Tags, _ = addTag(task.Tags, tag) // single words from ParseDescription
+		}
+		if project != "" {
+			task.Project = project
+		}
+	}
+	if e.Due != nil {
+		task.Due = *e.Due
+	}
+	if e.Priority != nil {
+		task.Priority = *e.Priority
+	}
+	if e.Project != nil {
+		task.Project = strings.TrimPrefix(*e.Project, "@")
+	}
+	if e.Recur != nil {
+		task.Recur = ""
+		if *e.Recur != "" {
+			r, err := ParseRecurrence(*e.Recur)
+			if err != nil {
+				return Tasks{}, err
+			}
+			task.Recur = r.String()
+		}
+	}
+	for _, tag := range e.AddTags {
+		var err error
+		if task.Tags, err = addTag(task.Tags, tag); err != nil {
+			return Tasks{}, err
+		}
+	}
+	for _, tag := range e.RemoveTags {
+		tag = strings.TrimPrefix(tag, "+")
+		task.Tags = slices.DeleteFunc(task.Tags, func(t string) bool { return t == tag })
+	}
+	if e.Parent != nil {
+		task.Parent = *e.Parent
+	}
+	for _, id := range e.AddBlockers {
+		if !slices.Contains(task.BlockedBy, id) {
+			task.BlockedBy = append(task.BlockedBy, id)
+		}
+	}
+	task.BlockedBy = slices.DeleteFunc(task.BlockedBy, func(id int) bool { return slices.Contains(e.RemoveBlockers, id) })
+	return task, nil
+}
+
+// FormatEditable renders a task as text for editing in $EDITOR. The result
+// reads back with ParseEditable.
+func FormatEditable(t Tasks) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Editing task %d. Lines starting with # are ignored.\n", t.ID)
+	fmt.Fprintf(&b, "# Leave a field empty to clear it; due accepts the same dates as add.\n")
+	fmt.Fprintf(&b, "description: %s\n", t.Description)
+	fmt.Fprintf(&b, "due: %s\n", formatEditableDue(t.Due))
+	fmt.Fprintf(&b, "priority: %s\n", t.Priority)
+	fmt.Fprintf(&b, "tags: %s\n", joinTags(t.Tags))
+	fmt.Fprintf(&b, "project: %s\n", t.Project)
+	fmt.Fprintf(&b, "every: %s\n", t.Recur)
+	fmt.Fprintf(&b, "parent: %s\n", formatOptionalID(t.Parent))
+	fmt.Fprintf(&b, "blocked by: %s\n", joinIDs(t.BlockedBy))
+	return b.String()
+}
+
+// formatEditableDue writes a due date as a day, or to the minute when it
+// has a time of day.
+func formatEditableDue(due time.Time) string {
+	switch {
+	case due.IsZero():
+		return ""
+	case due.Equal(endOfDay(due)):
+		return due.Format("2006-01-02")
+	}
+	return due.Format("2006-01-02 15:04")
+}
+
+// ParseEditable reads text written by FormatEditable back, returning only
+// the fields that differ from the original task so that other changes
+// made to it in the meantime are kept.
+func ParseEditable(text string, original Tasks, now time.Time) (TaskEdit, error) {
+	var edit TaskEdit
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for line := 1; scanner.Scan(); line++ {
+		row := strings.TrimSpace(scanner.Text())
+		if row == "" || strings.HasPrefix(row, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(row, ":")
+		if !ok {
+			return TaskEdit{}, fmt.Errorf("line %d: want \"field: value\", got %q", line, row)
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "description":
+			if value != original.Description {
+				edit.Description = &value
+			}
+		case "due":
+			// Compared as written, as the seconds of the original are not
+			if value == formatEditableDue(original.Due) {
+				continue
+			}
+			var due time.Time
+			if value != "" {
+				var err error
+				if due, err = ParseDue(value, now); err != nil {
+					return TaskEdit{}, fmt.Errorf("line %d: %w", line, err)
+				}
+			}
+			if !due.Equal(original.Due) {
+				edit.Due = &due
+			}
+		case "priority":
+			priority, err := ParsePriority(value)
+			if err != nil {
+				return TaskEdit{}, fmt.Errorf("line %d: %w", line, err)
+			}
+			if priority != original.Priority {
+				edit.Priority = &priority
+			}
+		case "tags":
+			var tags []string
+			for _, tag := range strings.Fields(value) {
+				tags, _ = addTag(tags, tag) // fields hold no spaces
+			}
+			for _, tag := range tags {
+				if !slices.Contains(original.Tags, tag) {
+					edit.AddTags = append(edit.AddTags, tag)
+				}
+			}
+			for _, tag := range original.Tags {
+				if !slices.Contains(tags, tag) {
+					edit.RemoveTags = append(edit.RemoveTags, tag)
+				}
+			}
+		case "project":
+			value = strings.TrimPrefix(value, "@")
+			if value != original.Project {
+				edit.Project = &value
+			}
+		case "every":
+			if value != "" {
+				r, err := ParseRecurrence(value)
+				if err != nil {
+					return TaskEdit{}, fmt.Errorf("line %d: %w", line, err)
+				}
+				value = r.String()
+			}
+			if value != original.Recur {
+				edit.Recur = &value
+			}
+		case "parent":
+			parent := 0
+			if value != "" {
+				var err error
+				if parent, err = strconv.Atoi(value); err != nil || parent < 1 {
+					return TaskEdit{}, fmt.Errorf("line %d: invalid task ID %q", line, value)
+				}
+			}
+			if parent != original.Parent {
+				edit.Parent = &parent
+			}
+		case "blocked by":
+			blockers, err := splitIDs(strings.ReplaceAll(value, ",", " "))
+			if err != nil {
+				return TaskEdit{}, fmt.Errorf("line %d: %w", line, err)
+			}
+			for _, id := range blockers {
+				if !slices.Contains(original.BlockedBy, id) {
+					edit.AddBlockers = append(edit.AddBlockers, id)
+				}
+			}
+			for _, id := range original.BlockedBy {
+				if !slices.Contains(blockers, id) {
+					edit.RemoveBlockers = append(edit.RemoveBlockers, id)
+				}
+			}
+		default:
+			return TaskEdit{}, fmt.Errorf("line %d: unknown field %q", line, key)
+		}
+	}
+	return edit, scanner.Err()
+}
@@ -0,0 +1,94 @@
+package tasks_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+)
+
+func TestEditTask(t *testing.T) {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	s := tasks.NewMemoryStore(tasks.Tasks{
+		ID: 1, Description: "Tidy desk", CreatedAt: created,
+		Tags: []string{"home", "chore"}, Due: time.Now(),
+	})
+
+	description := "Tidy office +work @admin"
+	var noDue time.Time
+	task, err := tasks.EditTask(s, 1, tasks.TaskEdit{
+		Description: &description,
+		Due:         &noDue,
+		RemoveTags:  []string{"+home"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Description != "Tidy office" || task.Project != "admin" || !task.Due.IsZero() {
+		t.Fatalf("unexpected task %+v", task)
+	}
+	if !slices.Equal(task.Tags, []string{"chore", "work"}) {
+		t.Fatalf("got tags %v", task.Tags)
+	}
+	if stored, _ := s.Get(1); !stored.CreatedAt.Equal(created) || stored.Description != "Tidy office" {
+		t.Fatalf("stored task %+v", stored)
+	}
+
+	empty := " +tag "
+	if _, err := tasks.EditTask(s, 1, tasks.TaskEdit{Description: &empty}); err == nil {
+		t.Fatal("expected error for empty description")
+	}
+}
+
+func TestParseEditable(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	original := tasks.Tasks{
+		ID: 4, Description: "Write report", Priority: tasks.PriorityLow,
+		Tags: []string{"work", "q4"}, Due: time.Date(2026, 10, 20, 23, 59, 59, 0, time.UTC),
+	}
+
+	// Unchanged text is an empty edit
+	text := tasks.FormatEditable(original)
+	edit, err := tasks.ParseEditable(text, original, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !edit.Empty() {
+		t.Fatalf("got %+v for unchanged text", edit)
+	}
+
+	// Also when the due time has seconds the form leaves out
+	timed := original
+	timed.Due = time.Date(2026, 10, 20, 14, 34, 56, 0, time.UTC)
+	if edit, err := tasks.ParseEditable(tasks.FormatEditable(timed), timed, now); err != nil || !edit.Empty() {
+		t.Fatalf("got %+v, %v for unchanged text with a due time", edit, err)
+	}
+
+	text = strings.NewReplacer(
+		"priority: low", "priority: high",
+		"tags: work q4", "tags: work urgent",
+		"due: 2026-10-20", "due: ",
+	).Replace(text)
+	edit, err = tasks.ParseEditable(text, original, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edit.Description != nil || edit.Project != nil {
+		t.Fatalf("unchanged fields in edit: %+v", edit)
+	}
+	if edit.Priority == nil || *edit.Priority != tasks.PriorityHigh {
+		t.Fatalf("got priority %v", edit.Priority)
+	}
+	if edit.Due == nil || !edit.Due.IsZero() {
+		t.Fatalf("got due %v", edit.Due)
+	}
+	if !slices.Equal(edit.AddTags, []string{"urgent"}) || !slices.Equal(edit.RemoveTags, []string{"q4"}) {
+		t.Fatalf("got tags +%v -%v", edit.AddTags, edit.RemoveTags)
+	}
+
+	if _, err := tasks.ParseEditable("colour: red\n", original, now); err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+}
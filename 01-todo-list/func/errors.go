@@ -0,0 +1,93 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound is returned when no task has the requested ID.
+	ErrNotFound = errors.New("task not found")
+
+	// ErrCorruptRecord is returned when stored data cannot be parsed.
+	ErrCorruptRecord = errors.New("corrupt record")
+
+	// ErrNoTimer is returned when stopping a timer while none runs.
+	ErrNoTimer = errors.New("no timer running")
+
+	// ErrLocked is returned when another process holds the data file for
+	// longer than LockTimeout.
+	ErrLocked = errors.New("database is locked")
+
+	// ErrNoRemote is returned when syncing a data file that has no git
+	// repository set up.
+	ErrNoRemote = errors.New("no repository set up to sync with")
+)
+
+// notFoundError names the ID that was looked up and matches ErrNotFound.
+type notFoundError struct {
+	id int
+}
+
+func (e notFoundError) Error() string {
+	return fmt.Sprintf("task %d not found", e.id)
+}
+
+func (e notFoundError) Unwrap() error {
+	return ErrNotFound
+}
+
+func notFound(id int) error {
+	return notFoundError{id: id}
+}
+
+// lockedError names the process holding a lock, when known, and matches
+// ErrLocked. Readers are not named, only told apart from writers.
+type lockedError struct {
+	pid     int
+	readers bool
+}
+
+func (e lockedError) Error() string {
+	switch {
+	case e.readers:
+		return "database is locked by other processes reading it"
+	case e.pid == 0:
+		return "database is locked by another process"
+	}
+	return fmt.Sprintf("database is locked by PID %d", e.pid)
+}
+
+func (e lockedError) Unwrap() error {
+	return ErrLocked
+}
+
+func errTaskExists(id int) error {
+	return fmt.Errorf("task %d already exists", id)
+}
+
+var (
+	errMissingHeader = errors.New("missing header")
+	errMissingField  = errors.New("missing field")
+	errDuplicateID   = errors.New("duplicate ID")
+)
+
+// RecordError describes a bad row of a CSV data file. It matches
+// ErrCorruptRecord as well as the underlying parse error.
+type RecordError struct {
+	Line  int    // 1-based line number in the file
+	Field string // column name, "header" for header problems
+	Value string
+	Err   error
+}
+
+func (e *RecordError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("line %d: %s: %v", e.Line, e.Field, e.Err)
+	}
+	return fmt.Sprintf("line %d: %s: %v (%q)", e.Line, e.Field, e.Err, e.Value)
+}
+
+func (e *RecordError) Unwrap() []error {
+	return []error{ErrCorruptRecord, e.Err}
+}
@@ -0,0 +1,164 @@
+package tasks
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Formats understood by ExportTasks and ImportTasks
+const (
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatTodoTxt  = "todo.txt"
+	FormatMarkdown = "markdown"
+)
+
+// todoTxtPriorities maps priorities onto the letters of todo.txt.
+var todoTxtPriorities = map[Priority]string{
+	PriorityHigh:   "A",
+	PriorityMedium: "B",
+	PriorityLow:    "C",
+}
+
+func unknownFormat(format string) error {
+	return fmt.Errorf("unknown format %q (want %s, %s, %s or %s)", format, FormatJSON, FormatCSV, FormatTodoTxt, FormatMarkdown)
+}
+
+// FormatFromPath guesses the format of a file from its name.
+func FormatFromPath(path string) (string, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json", ".jsonl":
+		return FormatJSON, nil
+	case ".csv":
+		return FormatCSV, nil
+	case ".txt":
+		return FormatTodoTxt, nil
+	case ".md", ".markdown":
+		return FormatMarkdown, nil
+	}
+	return "", fmt.Errorf("cannot tell the format of %s from its name, use --format", path)
+}
+
+// ExportTasks writes tasks in one of the exchange formats. json and csv
+// keep every field; todo.txt and markdown are meant for other tools and
+// for people, and keep what those formats can express:
+//
+//	todo.txt: x 2026-10-18 2026-10-01 Write report +office @work due:2026-10-20
+//	markdown: - [x] Write report +work @office (due 2026-10-20, high)
+//
+// todo.txt calls projects +project and tags @context, the other way round
+// from task descriptions. Markdown checklists nest subtasks under their
+// parents.
+func ExportTasks(w io.Writer, tasks []Tasks, format string) error {
+	switch format {
+	case FormatJSON:
+		rows := make([]taskOutput, 0, len(tasks))
+		for _, task := range tasks {
+			rows = append(rows, newTaskOutput(task))
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	case FormatCSV:
+		return writeCSV(w, tasks)
+	case FormatTodoTxt:
+		bw := bufio.NewWriter(w)
+		for _, task := range tasks {
+			fmt.Fprintln(bw, todoTxtLine(task))
+		}
+		return bw.Flush()
+	case FormatMarkdown:
+		return writeMarkdown(w, tasks)
+	}
+	return unknownFormat(format)
+}
+
+func todoTxtLine(t Tasks) string {
+	var words []string
+	priority, hasPriority := todoTxtPriorities[t.Priority]
+	if t.Completed() {
+		words = append(words, "x", t.CompletedAt.Format("2006-01-02"))
+	} else if hasPriority {
+		words = append(words, "("+priority+")")
+	}
+	words = append(words, t.CreatedAt.Format("2006-01-02"), t.Description)
+
+	if t.Project != "" {
+		words = append(words, "+"+t.Project)
+	}
+	for _, tag := range t.Tags {
+		words = append(words, "@"+tag)
+	}
+	// Completed tasks lose their (A) in todo.txt, so keep it as a tag
+	if t.Completed() && hasPriority {
+		words = append(words, "pri:"+priority)
+	}
+	if !t.Due.IsZero() {
+		words = append(words, "due:"+t.Due.Format("2006-01-02"))
+	}
+	if t.Recur != "" && !strings.Contains(t.Recur, " ") {
+		words = append(words, "rec:"+t.Recur)
+	}
+	return strings.Join(words, " ")
+}
+
+func writeMarkdown(w io.Writer, tasks []Tasks) error {
+	byID := make(map[int]Tasks, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+	depth := func(t Tasks) int {
+		n := 0
+		for id := t.Parent; id != 0 && n < len(tasks); id = byID[id].Parent {
+			if _, ok := byID[id]; !ok {
+				break
+			}
+			n++
+		}
+		return n
+	}
+
+	bw := bufio.NewWriter(w)
+	ordered, _ := treeOrder(tasks)
+	for _, task := range ordered {
+		check := " "
+		if task.Completed() {
+			check = "x"
+		}
+		fmt.Fprintf(bw, "%s- [%s] %s\n", strings.Repeat("  ", depth(task)), check, markdownText(task))
+	}
+	return bw.Flush()
+}
+
+// markdownText writes the task as it would be typed, followed by its due
+// date, priority and recurrence in parentheses.
+func markdownText(t Tasks) string {
+	words := []string{t.Description}
+	for _, tag := range t.Tags {
+		words = append(words, "+"+tag)
+	}
+	if t.Project != "" {
+		words = append(words, "@"+t.Project)
+	}
+	text := strings.Join(words, " ")
+
+	var details []string
+	if !t.Due.IsZero() {
+		details = append(details, "due "+t.Due.Format("2006-01-02"))
+	}
+	if t.Priority != PriorityNone {
+		details = append(details, t.Priority.String())
+	}
+	// Last, as weekday lists and cron specs may hold commas
+	if t.Recur != "" {
+		details = append(details, "every "+t.Recur)
+	}
+	if len(details) > 0 {
+		text += " (" + strings.Join(details, ", ") + ")"
+	}
+	return text
+}
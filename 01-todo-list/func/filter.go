@@ -0,0 +1,220 @@
+package tasks
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Sort keys accepted by ListOptions.SortBy and the sort: query field; a
+// leading - sorts in descending order.
+const (
+	SortByID       = "id"
+	SortByCreated  = "created"
+	SortByDue      = "due"
+	SortByPriority = "priority"
+)
+
+// Groupings accepted by ListOptions.GroupBy
+const (
+	GroupByProject  = "project"
+	GroupByTag      = "tag"
+	GroupByPriority = "priority"
+)
+
+// ListOptions narrows down, orders and groups the tasks shown by the list views.
+type ListOptions struct {
+	Status     string        // StatusOpen, StatusDone or StatusAll, all when empty
+	Query      string        // query terms as understood by ParseQuery
+	Overdue    bool          // only open tasks past their due date
+	DueWithin  time.Duration // only open tasks due within this window, overdue included
+	Tags       []string      // only tasks carrying every one of these tags
+	Project    string        // only tasks in this project
+	Priority   Priority      // only tasks with this priority
+	IDs        []int         // only these tasks
+	OlderThan  time.Duration // only tasks completed, or if open created, longer ago than this
+	Actionable bool          // only open tasks without open subtasks or blockers
+	SortBy     string        // comma separated sort keys, defaults to id
+	GroupBy    string        // one of the GroupBy constants, no grouping when empty
+	Tree       bool          // order subtasks under their parents
+}
+
+// Overdue reports whether an open task is past its due date.
+func (t Tasks) Overdue(now time.Time) bool {
+	return !t.Completed() && !t.Due.IsZero() && t.Due.Before(now)
+}
+
+// olderThan reports whether the task was completed, or while open created,
+// before the given time.
+func (t Tasks) olderThan(before time.Time) bool {
+	if t.Completed() {
+		return t.CompletedAt.Before(before)
+	}
+	return t.CreatedAt.Before(before)
+}
+
+// resolve parses the query, letting its status, sort and group terms
+// override the matching options.
+func (o ListOptions) resolve(now time.Time) (ListOptions, Query, error) {
+	q, err := ParseQuery(o.Query, now)
+	if err != nil {
+		return o, Query{}, err
+	}
+	if q.Status != "" {
+		o.Status = q.Status
+	}
+	if q.GroupBy != "" {
+		o.GroupBy = q.GroupBy
+	}
+	if len(q.sorts) > 0 {
+		o.SortBy = strings.Join(q.sorts, ",")
+	}
+	if o.SortBy == "" {
+		o.SortBy = SortByID
+	}
+	return o, q, nil
+}
+
+// apply filters and sorts tasks according to resolved options and query.
+func (o ListOptions) apply(tasks []Tasks, q Query, now time.Time) ([]Tasks, error) {
+	canStart := func(Tasks) bool { return true }
+	if o.Actionable {
+		canStart = actionable(tasks)
+	}
+
+	var filtered []Tasks
+	for _, task := range tasks {
+		if o.Status == StatusOpen && task.Completed() || o.Status == StatusDone && !task.Completed() {
+			continue
+		}
+		if o.Overdue && !task.Overdue(now) {
+			continue
+		}
+		if o.DueWithin > 0 && (task.Completed() || task.Due.IsZero() || task.Due.After(now.Add(o.DueWithin))) {
+			continue
+		}
+		if slices.ContainsFunc(o.Tags, func(tag string) bool { return !task.HasTag(tag) }) {
+			continue
+		}
+		if o.Project != "" && task.Project != strings.TrimPrefix(o.Project, "@") {
+			continue
+		}
+		if o.Priority != PriorityNone && task.Priority != o.Priority {
+			continue
+		}
+		if len(o.IDs) > 0 && !slices.Contains(o.IDs, task.ID) {
+			continue
+		}
+		if o.OlderThan > 0 && !task.olderThan(now.Add(-o.OlderThan)) {
+			continue
+		}
+		if !canStart(task) {
+			continue
+		}
+		if !q.Match(task) {
+			continue
+		}
+		filtered = append(filtered, task)
+	}
+
+	if err := sortTasks(filtered, strings.Split(o.SortBy, ",")); err != nil {
+		return nil, err
+	}
+	return filtered, nil
+}
+
+// compareDue orders tasks by due date, leaving tasks without one last.
+func compareDue(a, b Tasks) int {
+	switch {
+	case a.Due.IsZero() && b.Due.IsZero():
+		return 0
+	case a.Due.IsZero():
+		return 1
+	case b.Due.IsZero():
+		return -1
+	}
+	return a.Due.Compare(b.Due)
+}
+
+// noGroup titles the tasks lacking the value grouped by.
+const noGroup = "(none)"
+
+// TaskGroup is a titled slice of tasks for grouped output. Name is empty
+// when the tasks are not grouped.
+type TaskGroup struct {
+	Name  string
+	Tasks []Tasks
+
+	prefix map[int]string // tree drawn before each description, see treeOrder
+}
+
+// groupTasks splits tasks by the given grouping, keeping their order within
+// each group. A task with several tags appears under each of them.
+func groupTasks(tasks []Tasks, by string) ([]TaskGroup, error) {
+	var keys func(Tasks) []string
+	switch by {
+	case "":
+		return []TaskGroup{{Tasks: tasks}}, nil
+	case GroupByProject:
+		keys = func(t Tasks) []string {
+			if t.Project == "" {
+				return []string{noGroup}
+			}
+			return []string{"@" + t.Project}
+		}
+	case GroupByTag:
+		keys = func(t Tasks) []string {
+			if len(t.Tags) == 0 {
+				return []string{noGroup}
+			}
+			var tags []string
+			for _, tag := range t.Tags {
+				tags = append(tags, "+"+tag)
+			}
+			return tags
+		}
+	case GroupByPriority:
+		keys = func(t Tasks) []string {
+			if t.Priority == PriorityNone {
+				return []string{noGroup}
+			}
+			return []string{t.Priority.String()}
+		}
+	default:
+		return nil, fmt.Errorf("cannot group by %q (want %s, %s or %s)", by, GroupByProject, GroupByTag, GroupByPriority)
+	}
+
+	index := make(map[string]int)
+	var groups []TaskGroup
+	for _, task := range tasks {
+		for _, key := range keys(task) {
+			i, ok := index[key]
+			if !ok {
+				i = len(groups)
+				index[key] = i
+				groups = append(groups, TaskGroup{Name: key})
+			}
+			groups[i].Tasks = append(groups[i].Tasks, task)
+		}
+	}
+
+	// Named groups first, then tasks without a value; priorities rank high
+	// to low rather than by name.
+	slices.SortFunc(groups, func(a, b TaskGroup) int {
+		if (a.Name == noGroup) != (b.Name == noGroup) {
+			if a.Name == noGroup {
+				return 1
+			}
+			return -1
+		}
+		if by == GroupByPriority {
+			pa, _ := ParsePriority(a.Name)
+			pb, _ := ParsePriority(b.Name)
+			return int(pb - pa)
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return groups, nil
+}
@@ -0,0 +1,446 @@
+package tasks
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ImportResult is what ImportTasks did.
+type ImportResult struct {
+	Added      []Tasks // as stored, under their new IDs
+	Duplicates []Tasks // as read, skipped because a matching task exists
+}
+
+// ImportTasks reads tasks written in one of the exchange formats and adds
+// them to s in one transaction. Imported tasks get new IDs; their parents,
+// blockers and series are remapped to match, and links to tasks missing
+// from the file are dropped.
+//
+// A task with the same description and project as one already in s, or
+// earlier in the file, is a duplicate and skipped unless allowDuplicates is
+// set; links to it point at the task it duplicates. With dryRun set nothing
+// is stored and the IDs are those the tasks would most likely get.
+func ImportTasks(s Store, r io.Reader, format string, dryRun, allowDuplicates bool) (ImportResult, error) {
+	now := time.Now()
+	imported, err := readImport(r, format, now)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	if dryRun {
+		existing, err := s.List()
+		if err != nil {
+			return ImportResult{}, err
+		}
+		s = NewMemoryStore(existing...)
+	}
+
+	var result ImportResult
+	err = s.Batch(func(tx Store) error {
+		result = ImportResult{}
+		existing, err := tx.List()
+		if err != nil {
+			return err
+		}
+		seen := make(map[string]int, len(existing))
+		for _, task := range existing {
+			seen[duplicateKey(task)] = task.ID
+		}
+
+		// Store the tasks first, then point their links at the new IDs
+		ids := make(map[int]int, len(imported))
+		var sources []Tasks
+		for _, task := range imported {
+			if id, ok := seen[duplicateKey(task)]; ok && !allowDuplicates {
+				ids[task.ID] = id
+				result.Duplicates = append(result.Duplicates, task)
+				continue
+			}
+			added := cloneTask(task)
+			added.ID, added.Parent, added.BlockedBy, added.Series = 0, 0, nil, 0
+			if added, err = tx.Create(added); err != nil {
+				return err
+			}
+			ids[task.ID] = added.ID
+			seen[duplicateKey(task)] = added.ID
+			result.Added = append(result.Added, added)
+			sources = append(sources, task)
+		}
+
+		for i, task := range result.Added {
+			source := sources[i]
+			task.Parent = ids[source.Parent]
+			task.Series = ids[source.Series]
+			for _, id := range source.BlockedBy {
+				if blocker := ids[id]; blocker != 0 && !slices.Contains(task.BlockedBy, blocker) {
+					task.BlockedBy = append(task.BlockedBy, blocker)
+				}
+			}
+			if task.Parent == 0 && task.Series == 0 && len(task.BlockedBy) == 0 {
+				continue
+			}
+			if err := tx.Update(task); err != nil {
+				return err
+			}
+			result.Added[i] = task
+		}
+		return nil
+	})
+	if err != nil {
+		return ImportResult{}, err
+	}
+	return result, nil
+}
+
+// duplicateKey identifies tasks that are the same for import: the same
+// description, ignoring case and spacing, in the same project.
+func duplicateKey(t Tasks) string {
+	description := strings.ToLower(strings.Join(strings.Fields(t.Description), " "))
+	return description + "\x00" + t.Project
+}
+
+// readImport parses tasks in the given format. Every task gets an ID that
+// is unique within the file; formats without IDs number them in order.
+func readImport(r io.Reader, format string, now time.Time) ([]Tasks, error) {
+	var (
+		tasks []Tasks
+		err   error
+	)
+	switch format {
+	case FormatJSON:
+		tasks, err = parseJSON(r)
+	case FormatCSV:
+		tasks, err = parseCSV(r, now)
+	case FormatTodoTxt:
+		tasks, err = parseTodoTxt(r, now)
+	case FormatMarkdown:
+		tasks, err = parseMarkdown(r, now)
+	default:
+		return nil, unknownFormat(format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]bool, len(tasks))
+	next := 1
+	for _, task := range tasks {
+		next = max(next, task.ID+1)
+	}
+	for i, task := range tasks {
+		if task.ID == 0 {
+			tasks[i].ID = next
+			next++
+		} else if seen[task.ID] {
+			return nil, fmt.Errorf("task %d: %w", task.ID, errDuplicateID)
+		}
+		seen[tasks[i].ID] = true
+		if task.CreatedAt.IsZero() {
+			tasks[i].CreatedAt = now
+		}
+	}
+	return tasks, nil
+}
+
+// parseJSON reads an array of tasks, as written by export and --output
+// json, or one task per line as written by --output jsonl.
+func parseJSON(r io.Reader) ([]Tasks, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []taskOutput
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		if err := json.Unmarshal(data, &rows); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCorruptRecord, err)
+		}
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		for {
+			var row taskOutput
+			err := dec.Decode(&row)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrCorruptRecord, err)
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	tasks := make([]Tasks, 0, len(rows))
+	for i, row := range rows {
+		task, err := row.task()
+		if err != nil {
+			return nil, fmt.Errorf("task %d: %w", i+1, err)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// task reads the machine-readable form of a task back.
+func (o taskOutput) task() (Tasks, error) {
+	t := Tasks{
+		ID:          o.ID,
+		Description: strings.TrimSpace(o.Description),
+		Project:     o.Project,
+		Series:      o.Series,
+		Parent:      o.Parent,
+		BlockedBy:   o.BlockedBy,
+	}
+	if t.Description == "" {
+		return Tasks{}, errors.New("empty description")
+	}
+	var err error
+	for _, tag := range o.Tags {
+		if t.Tags, err = addTag(t.Tags, tag); err != nil {
+			return Tasks{}, fmt.Errorf("tags: %w", err)
+		}
+	}
+	if t.CreatedAt, err = parseOptionalTime(o.CreatedAt); err != nil {
+		return Tasks{}, fmt.Errorf("created_at: %w", err)
+	}
+	if t.CompletedAt, err = parseOptionalTime(o.CompletedAt); err != nil {
+		return Tasks{}, fmt.Errorf("completed_at: %w", err)
+	}
+	if t.Due, err = parseOptionalTime(o.Due); err != nil {
+		return Tasks{}, fmt.Errorf("due: %w", err)
+	}
+	if t.UpdatedAt, err = parseOptionalTime(o.UpdatedAt); err != nil {
+		return Tasks{}, fmt.Errorf("updated_at: %w", err)
+	}
+	if t.Priority, err = ParsePriority(o.Priority); err != nil {
+		return Tasks{}, err
+	}
+	if o.State != "" && o.State != StateTodo && o.State != StateDone {
+		if err := checkStateName(o.State); err != nil {
+			return Tasks{}, err
+		}
+		t.State = o.State
+	}
+	for _, row := range o.Transitions {
+		at, err := time.Parse(time.RFC3339, row.At)
+		if err != nil {
+			return Tasks{}, fmt.Errorf("transitions: %w", err)
+		}
+		if err := checkStateName(row.State); err != nil {
+			return Tasks{}, err
+		}
+		t.Transitions = append(t.Transitions, Transition{State: row.State, At: at})
+	}
+	for _, row := range o.TimeLog {
+		start, err := time.Parse(time.RFC3339, row.Start)
+		if err != nil {
+			return Tasks{}, fmt.Errorf("time_log: %w", err)
+		}
+		end, err := parseOptionalTime(row.End)
+		if err != nil {
+			return Tasks{}, fmt.Errorf("time_log: %w", err)
+		}
+		t.TimeLog = append(t.TimeLog, TimeEntry{Start: start, End: end})
+	}
+	if o.Recur != "" {
+		r, err := ParseRecurrence(o.Recur)
+		if err != nil {
+			return Tasks{}, err
+		}
+		t.Recur = r.String()
+	}
+	return t, nil
+}
+
+// parseTodoTxt reads the todo.txt format written by ExportTasks. Key:value
+// words other than due:, rec: and pri:, or with values that do not parse,
+// stay in the description.
+func parseTodoTxt(r io.Reader, now time.Time) ([]Tasks, error) {
+	var tasks []Tasks
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		words := strings.Fields(scanner.Text())
+		if len(words) == 0 {
+			continue
+		}
+
+		var task Tasks
+		if words[0] == "x" {
+			task.CompletedAt = now
+			words = words[1:]
+			if date, ok := leadingDate(words); ok {
+				task.CompletedAt = date
+				words = words[1:]
+			}
+		} else if len(words[0]) == 3 && words[0][0] == '(' && words[0][2] == ')' {
+			if p, ok := todoTxtPriority(words[0][1:2]); ok {
+				task.Priority = p
+				words = words[1:]
+			}
+		}
+		if date, ok := leadingDate(words); ok {
+			task.CreatedAt = date
+			words = words[1:]
+		}
+
+		var description []string
+		for _, word := range words {
+			key, value, _ := strings.Cut(word, ":")
+			switch {
+			case len(word) > 1 && word[0] == '+':
+				task.Project = word[1:]
+				continue
+			case len(word) > 1 && word[0] == '@':
+				task.Tags, _ = addTag(task.Tags, word[1:]) // words hold no spaces
+				continue
+			case key == "due":
+				if due, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+					task.Due = endOfDay(due)
+					continue
+				}
+			case key == "rec":
+				if r, err := ParseRecurrence(value); err == nil {
+					task.Recur = r.String()
+					continue
+				}
+			case key == "pri":
+				if p, ok := todoTxtPriority(value); ok {
+					task.Priority = p
+					continue
+				}
+			}
+			description = append(description, word)
+		}
+		task.Description = strings.Join(description, " ")
+		if task.Description == "" {
+			return nil, fmt.Errorf("line %d: empty description", line)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, scanner.Err()
+}
+
+// leadingDate parses a todo.txt date at the start of words.
+func leadingDate(words []string) (time.Time, bool) {
+	if len(words) == 0 {
+		return time.Time{}, false
+	}
+	date, err := time.ParseInLocation("2006-01-02", words[0], time.Local)
+	return date, err == nil
+}
+
+// todoTxtPriority reads a todo.txt priority letter. Letters past C are
+// kept as low.
+func todoTxtPriority(letter string) (Priority, bool) {
+	if len(letter) != 1 || letter[0] < 'A' || letter[0] > 'Z' {
+		return PriorityNone, false
+	}
+	for p, l := range todoTxtPriorities {
+		if l == letter {
+			return p, true
+		}
+	}
+	return PriorityLow, true
+}
+
+var markdownItem = regexp.MustCompile(`^(\s*)[-*+] \[([ xX])\] (.+)$`)
+
+// parseMarkdown reads checklist items, written by ExportTasks or by hand.
+// Items nested under another become its subtasks; other lines are skipped.
+func parseMarkdown(r io.Reader, now time.Time) ([]Tasks, error) {
+	type open struct{ indent, id int }
+	var (
+		tasks   []Tasks
+		parents []open // items the next one may be nested under
+	)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		item := markdownItem.FindStringSubmatch(scanner.Text())
+		if item == nil {
+			continue
+		}
+
+		task := Tasks{ID: len(tasks) + 1}
+		if item[2] != " " {
+			task.CompletedAt = now
+		}
+		text := item[3]
+		if rest, details, ok := cutDetails(text); ok && applyDetails(&task, details) {
+			text = rest
+		}
+		task.Description, task.Tags, task.Project = ParseDescription(text)
+		if task.Description == "" {
+			return nil, fmt.Errorf("line %d: empty description", line)
+		}
+
+		indent := len(strings.ReplaceAll(item[1], "\t", "    "))
+		for len(parents) > 0 && parents[len(parents)-1].indent >= indent {
+			parents = parents[:len(parents)-1]
+		}
+		if len(parents) > 0 {
+			task.Parent = parents[len(parents)-1].id
+		}
+		parents = append(parents, open{indent, task.ID})
+		tasks = append(tasks, task)
+	}
+	return tasks, scanner.Err()
+}
+
+// cutDetails splits "text (details)" at its trailing parentheses.
+func cutDetails(text string) (rest, details string, ok bool) {
+	if !strings.HasSuffix(text, ")") {
+		return text, "", false
+	}
+	i := strings.LastIndex(text, " (")
+	if i < 0 {
+		return text, "", false
+	}
+	return text[:i], text[i+2 : len(text)-1], true
+}
+
+// applyDetails reads the details markdownText writes. When any part is not
+// understood the task is left alone, as the parentheses were most likely
+// part of the description.
+func applyDetails(task *Tasks, details string) bool {
+	var recur string
+	if i := strings.Index(details, "every "); i == 0 || i > 0 && strings.HasSuffix(details[:i], ", ") {
+		recur = details[i+len("every "):]
+		details = strings.TrimSuffix(details[:i], ", ")
+	}
+
+	parsed := *task
+	if recur != "" {
+		r, err := ParseRecurrence(recur)
+		if err != nil {
+			return false
+		}
+		parsed.Recur = r.String()
+	}
+	for _, part := range strings.Split(details, ", ") {
+		if part == "" && recur != "" {
+			continue
+		}
+		if date, ok := strings.CutPrefix(part, "due "); ok {
+			due, err := time.ParseInLocation("2006-01-02", date, time.Local)
+			if err != nil {
+				return false
+			}
+			parsed.Due = endOfDay(due)
+			continue
+		}
+		p, err := ParsePriority(part)
+		if err != nil || p == PriorityNone {
+			return false
+		}
+		parsed.Priority = p
+	}
+	*task = parsed
+	return true
+}
@@ -0,0 +1,128 @@
+package tasks_test
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	created := time.Date(2026, 10, 1, 9, 0, 0, 0, time.Local)
+	due := time.Date(2026, 11, 1, 23, 59, 59, 0, time.Local)
+	source := []tasks.Tasks{
+		{ID: 7, Description: "Plan party", CreatedAt: created, Project: "home"},
+		{ID: 9, Description: "Book venue", CreatedAt: created, Parent: 7, Due: due, Priority: tasks.PriorityHigh, Tags: []string{"calls"}},
+		{ID: 12, Description: "Send invites", CreatedAt: created, Parent: 7, CompletedAt: created.Add(time.Hour)},
+		{ID: 15, Description: "Water plants", CreatedAt: created, Recur: "mon,thu"},
+	}
+
+	for _, format := range []string{tasks.FormatJSON, tasks.FormatCSV, tasks.FormatTodoTxt, tasks.FormatMarkdown} {
+		t.Run(format, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := tasks.ExportTasks(&out, source, format); err != nil {
+				t.Fatal(err)
+			}
+			s := tasks.NewMemoryStore(tasks.Tasks{ID: 1, Description: "Existing", CreatedAt: created})
+			result, err := tasks.ImportTasks(s, &out, format, false, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Added) != len(source) {
+				t.Fatalf("added %d tasks, want %d", len(result.Added), len(source))
+			}
+
+			byDescription := make(map[string]tasks.Tasks)
+			for _, task := range result.Added {
+				if stored, _ := s.Get(task.ID); stored.Description != task.Description {
+					t.Errorf("task %d stored as %q, reported as %q", task.ID, stored.Description, task.Description)
+				}
+				byDescription[task.Description] = task
+			}
+			venue, party := byDescription["Book venue"], byDescription["Plan party"]
+			if venue.ID == 9 || venue.ID == 1 {
+				t.Errorf("imported task kept ID %d", venue.ID)
+			}
+			if !venue.Due.Equal(due) || venue.Priority != tasks.PriorityHigh || !slices.Equal(venue.Tags, []string{"calls"}) {
+				t.Errorf("Book venue lost fields: %+v", venue)
+			}
+			if party.Project != "home" || !byDescription["Send invites"].Completed() {
+				t.Errorf("project or completion lost: %+v", result.Added)
+			}
+			if byDescription["Water plants"].Recur != "mon,thu" {
+				t.Errorf("recurrence lost: %+v", byDescription["Water plants"])
+			}
+			// todo.txt has no subtasks
+			if format != tasks.FormatTodoTxt && venue.Parent != party.ID {
+				t.Errorf("Book venue has parent %d, want %d", venue.Parent, party.ID)
+			}
+		})
+	}
+}
+
+func TestImportSkipsDuplicates(t *testing.T) {
+	now := time.Now()
+	s := tasks.NewMemoryStore(tasks.Tasks{ID: 1, Description: "Plan party", CreatedAt: now})
+	checklist := `# Weekend
+
+- [ ] plan  party
+  - [ ] Book venue (due 2026-11-01, high)
+- [ ] Buy cake (for the party)
+- [ ] Buy cake (for the party)
+`
+	result, err := tasks.ImportTasks(s, strings.NewReader(checklist), tasks.FormatMarkdown, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Added) != 2 || len(result.Duplicates) != 2 {
+		t.Fatalf("added %d, skipped %d; want 2 and 2", len(result.Added), len(result.Duplicates))
+	}
+	venue, cake := result.Added[0], result.Added[1]
+	if venue.Parent != 1 {
+		t.Errorf("subtask of a duplicate has parent %d, want the existing task 1", venue.Parent)
+	}
+	if cake.Description != "Buy cake (for the party)" {
+		t.Errorf("got description %q, want the parentheses kept", cake.Description)
+	}
+
+	// A dry run reports the same without storing anything
+	before, _ := s.List()
+	result, err = tasks.ImportTasks(s, strings.NewReader("Call mum\n"), tasks.FormatTodoTxt, true, false)
+	if err != nil || len(result.Added) != 1 {
+		t.Fatalf("dry run: %v, %v", result, err)
+	}
+	if after, _ := s.List(); len(after) != len(before) {
+		t.Error("dry run stored tasks")
+	}
+}
+
+func TestParseTodoTxt(t *testing.T) {
+	todo := `(A) 2026-10-01 Call plumber +house @phone due:2026-10-20
+x 2026-10-05 2026-10-01 Pay bills +house @online pri:B
+(B) Review slides at:10am @work rec:1w
+`
+	s := tasks.NewMemoryStore()
+	result, err := tasks.ImportTasks(s, strings.NewReader(todo), tasks.FormatTodoTxt, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Added) != 3 {
+		t.Fatalf("added %d tasks, want 3", len(result.Added))
+	}
+
+	plumber, bills, slides := result.Added[0], result.Added[1], result.Added[2]
+	if plumber.Description != "Call plumber" || plumber.Project != "house" || plumber.Priority != tasks.PriorityHigh ||
+		!slices.Equal(plumber.Tags, []string{"phone"}) || plumber.Due.Format("2006-01-02") != "2026-10-20" ||
+		plumber.CreatedAt.Format("2006-01-02") != "2026-10-01" {
+		t.Errorf("plumber: %+v", plumber)
+	}
+	if bills.CompletedAt.Format("2006-01-02") != "2026-10-05" || bills.Priority != tasks.PriorityMedium {
+		t.Errorf("bills: %+v", bills)
+	}
+	if slides.Description != "Review slides at:10am" || slides.Recur != "1w" {
+		t.Errorf("slides: %+v", slides)
+	}
+}
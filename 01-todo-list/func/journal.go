@@ -0,0 +1,429 @@
+package tasks
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+)
+
+// Journal operations
+const (
+	OpCreate = "create"
+	OpUpdate = "update"
+	OpDelete = "delete"
+	OpUndo   = "undo"
+	OpRedo   = "redo"
+)
+
+// JournalEntry is one line of the journal. Changes made by the same command
+// share a group and are undone together.
+type JournalEntry struct {
+	Time   time.Time `json:"time"`
+	Group  int64     `json:"group"`
+	Action string    `json:"action"`           // command that made the change
+	Op     string    `json:"op"`               // one of the Op constants
+	Target int64     `json:"target,omitempty"` // group undone or redone
+	Before *Tasks    `json:"before,omitempty"` // nil for create
+	After  *Tasks    `json:"after,omitempty"`  // nil for delete
+}
+
+// taskID is the ID of the task the entry changed.
+func (e JournalEntry) taskID() int {
+	if e.After != nil {
+		return e.After.ID
+	}
+	if e.Before != nil {
+		return e.Before.ID
+	}
+	return 0
+}
+
+// JournalPath is where the journal of a data file is kept.
+func JournalPath(dataPath string) string {
+	return dataPath + ".journal"
+}
+
+// JournaledStore records every change made through it in an append-only
+// journal, so that whole commands can be undone and redone later. Tasks
+// created or updated through it, or by undo and redo, get UpdatedAt set.
+type JournaledStore struct {
+	inner   Store
+	path    string
+	action  string
+	group   int64
+	pending *[]JournalEntry // set inside Batch, written once it commits
+}
+
+// NewJournaledStore wraps s, recording changes as made by action in the
+// journal at path.
+func NewJournaledStore(s Store, path, action string) *JournaledStore {
+	return &JournaledStore{inner: s, path: path, action: action}
+}
+
+// Action returns a store journaling its changes as a separate command, for
+// callers such as the terminal UI that make many changes in one run.
+func (s *JournaledStore) Action(action string) *JournaledStore {
+	return NewJournaledStore(s.inner, s.path, action)
+}
+
+// Unwrap returns the store changes are passed on to.
+func (s *JournaledStore) Unwrap() Store {
+	return s.inner
+}
+
+func (s *JournaledStore) List() ([]Tasks, error) {
+	return s.inner.List()
+}
+
+func (s *JournaledStore) Get(id int) (Tasks, error) {
+	return s.inner.Get(id)
+}
+
+func (s *JournaledStore) Create(task Tasks) (Tasks, error) {
+	task, err := s.inner.Create(touch(task))
+	if err != nil {
+		return Tasks{}, err
+	}
+	return task, s.record(OpCreate, nil, &task)
+}
+
+// Update and Delete read the task as it was and change it in one batch, so
+// the journal holds the state actually replaced.
+func (s *JournaledStore) Update(task Tasks) error {
+	if s.pending == nil {
+		return s.Batch(func(tx Store) error { return tx.Update(task) })
+	}
+	before, err := s.inner.Get(task.ID)
+	if err != nil {
+		return err
+	}
+	task = touch(task)
+	if err := s.inner.Update(task); err != nil {
+		return err
+	}
+	return s.record(OpUpdate, &before, &task)
+}
+
+func (s *JournaledStore) Delete(id int) error {
+	if s.pending == nil {
+		return s.Batch(func(tx Store) error { return tx.Delete(id) })
+	}
+	before, err := s.inner.Get(id)
+	if err != nil {
+		return err
+	}
+	if err := s.inner.Delete(id); err != nil {
+		return err
+	}
+	return s.record(OpDelete, &before, nil)
+}
+
+// Batch journals the changes fn makes only once the batch has committed.
+func (s *JournaledStore) Batch(fn func(tx Store) error) error {
+	if s.pending != nil {
+		return fn(s)
+	}
+	var pending []JournalEntry
+	err := s.inner.Batch(func(tx Store) error {
+		return fn(&JournaledStore{inner: tx, path: s.path, action: s.action, group: s.groupID(), pending: &pending})
+	})
+	if err != nil {
+		return err
+	}
+	return s.append(pending...)
+}
+
+func (s *JournaledStore) Close() error {
+	return s.inner.Close()
+}
+
+// groupID returns the group of this store's changes, starting one on first use.
+func (s *JournaledStore) groupID() int64 {
+	if s.group == 0 {
+		s.group = time.Now().UnixNano()
+	}
+	return s.group
+}
+
+func (s *JournaledStore) record(op string, before, after *Tasks) error {
+	entry := JournalEntry{
+		Time:   time.Now(),
+		Group:  s.groupID(),
+		Action: s.action,
+		Op:     op,
+		Before: before,
+		After:  after,
+	}
+	if s.pending != nil {
+		*s.pending = append(*s.pending, entry)
+		return nil
+	}
+	return s.append(entry)
+}
+
+// append adds entries to the journal under its lock.
+func (s *JournaledStore) append(entries ...JournalEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	lock, err := loadFile(s.path+".lock", true)
+	if err != nil {
+		return err
+	}
+	defer closeFile(lock)
+	return s.write(entries)
+}
+
+// write appends entries; the caller must hold the journal lock.
+func (s *JournaledStore) write(entries []JournalEntry) error {
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// read loads the whole journal; a missing journal is empty.
+func (s *JournaledStore) read() ([]JournalEntry, error) {
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("journal line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// journalState is the journal replayed: the groups that can be undone and
+// redone, most recent last, and the entries of every change group.
+type journalState struct {
+	undo, redo []int64
+	groups     map[int64][]JournalEntry
+	order      []int64        // every group in the order it happened
+	undone     map[int64]bool // groups reverted, whether or not they can still be redone
+}
+
+func replay(entries []JournalEntry) journalState {
+	state := journalState{groups: make(map[int64][]JournalEntry), undone: make(map[int64]bool)}
+	for _, entry := range entries {
+		if _, seen := state.groups[entry.Group]; !seen {
+			state.order = append(state.order, entry.Group)
+		}
+		state.groups[entry.Group] = append(state.groups[entry.Group], entry)
+
+		switch entry.Op {
+		case OpUndo:
+			state.undo = slices.DeleteFunc(state.undo, func(g int64) bool { return g == entry.Target })
+			state.redo = append(state.redo, entry.Target)
+			state.undone[entry.Target] = true
+		case OpRedo:
+			state.redo = slices.DeleteFunc(state.redo, func(g int64) bool { return g == entry.Target })
+			state.undo = append(state.undo, entry.Target)
+			state.undone[entry.Target] = false
+		default:
+			if len(state.undo) == 0 || state.undo[len(state.undo)-1] != entry.Group {
+				state.undo = append(state.undo, entry.Group)
+				state.redo = nil // a new change forgets what was undone
+			}
+		}
+	}
+	return state
+}
+
+// Undo reverts the most recent command that changed tasks and returns its
+// journal entries.
+func (s *JournaledStore) Undo() ([]JournalEntry, error) {
+	return s.rewind(OpUndo)
+}
+
+// Redo applies the most recently undone command again.
+func (s *JournaledStore) Redo() ([]JournalEntry, error) {
+	return s.rewind(OpRedo)
+}
+
+func (s *JournaledStore) rewind(op string) ([]JournalEntry, error) {
+	lock, err := loadFile(s.path+".lock", true)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFile(lock)
+
+	entries, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	state := replay(entries)
+	stack := state.undo
+	if op == OpRedo {
+		stack = state.redo
+	}
+	if len(stack) == 0 {
+		return nil, fmt.Errorf("nothing to %s", op)
+	}
+	target := stack[len(stack)-1]
+	changes := state.groups[target]
+
+	err = s.inner.Batch(func(tx Store) error {
+		if op == OpUndo {
+			for _, change := range slices.Backward(changes) {
+				if err := revert(tx, change); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		for _, change := range changes {
+			if err := reapply(tx, change); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot %s %s: %w", op, changes[0].Action, err)
+	}
+
+	now := time.Now()
+	return changes, s.write([]JournalEntry{{
+		Time:   now,
+		Group:  now.UnixNano(),
+		Action: op,
+		Op:     op,
+		Target: target,
+	}})
+}
+
+func revert(s Store, change JournalEntry) error {
+	switch change.Op {
+	case OpCreate:
+		return s.Delete(change.After.ID)
+	case OpUpdate:
+		return s.Update(touch(*change.Before))
+	case OpDelete:
+		_, err := s.Create(touch(*change.Before))
+		return err
+	}
+	return nil
+}
+
+func reapply(s Store, change JournalEntry) error {
+	switch change.Op {
+	case OpCreate:
+		_, err := s.Create(touch(*change.After))
+		return err
+	case OpUpdate:
+		return s.Update(touch(*change.After))
+	case OpDelete:
+		return s.Delete(change.Before.ID)
+	}
+	return nil
+}
+
+// touch marks the task as changed now.
+func touch(task Tasks) Tasks {
+	task.UpdatedAt = time.Now()
+	return task
+}
+
+// touchingStore marks the tasks it writes as changed now, as JournaledStore
+// does, for changes made around the journal.
+type touchingStore struct {
+	Store
+}
+
+func (s touchingStore) Create(task Tasks) (Tasks, error) {
+	return s.Store.Create(touch(task))
+}
+
+func (s touchingStore) Update(task Tasks) error {
+	return s.Store.Update(touch(task))
+}
+
+func (s touchingStore) Batch(fn func(tx Store) error) error {
+	return s.Store.Batch(func(tx Store) error {
+		return fn(touchingStore{tx})
+	})
+}
+
+// HistoryItem is one command in the journal.
+type HistoryItem struct {
+	Time   time.Time `json:"time" yaml:"time"`
+	Action string    `json:"action" yaml:"action"`
+	Tasks  []int     `json:"tasks" yaml:"tasks"`
+	Undone bool      `json:"undone" yaml:"undone"` // reverted by undo
+}
+
+// History lists the journaled commands, newest first. Undo and redo appear
+// as their own items naming the tasks they touched.
+func (s *JournaledStore) History() ([]HistoryItem, error) {
+	lock, err := loadFile(s.path+".lock", false)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFile(lock)
+
+	entries, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	state := replay(entries)
+
+	var items []HistoryItem
+	for _, group := range slices.Backward(state.order) {
+		first := state.groups[group][0]
+		changes := state.groups[group]
+		if first.Op == OpUndo || first.Op == OpRedo {
+			changes = state.groups[first.Target]
+		}
+
+		item := HistoryItem{
+			Time:   first.Time,
+			Action: first.Action,
+			Undone: state.undone[group],
+			Tasks:  []int{},
+		}
+		for _, change := range changes {
+			if id := change.taskID(); !slices.Contains(item.Tasks, id) {
+				item.Tasks = append(item.Tasks, id)
+			}
+		}
+		if first.Op == OpUndo || first.Op == OpRedo {
+			item.Action = fmt.Sprintf("%s %s", first.Op, changes[0].Action)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,107 @@
+package tasks_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+)
+
+func TestJournalUndoRedo(t *testing.T) {
+	dir := t.TempDir()
+	inner := tasks.NewCSVStore(filepath.Join(dir, "db.csv"))
+	path := tasks.JournalPath(filepath.Join(dir, "db.csv"))
+
+	// Every command gets its own journaled store, as the CLI does
+	run := func(action string, fn func(s tasks.Store) error) {
+		t.Helper()
+		if err := fn(tasks.NewJournaledStore(inner, path, action)); err != nil {
+			t.Fatalf("%s: %v", action, err)
+		}
+	}
+	for _, description := range []string{"one", "two", "three"} {
+		run("add", func(s tasks.Store) error {
+			_, err := tasks.AddNewTask(s, tasks.Tasks{Description: description})
+			return err
+		})
+	}
+	run("complete", func(s tasks.Store) error {
+		_, _, err := tasks.CompleteTasks(s, tasks.ListOptions{IDs: []int{1, 2}}, false, false)
+		return err
+	})
+	run("delete", func(s tasks.Store) error { _, err := tasks.DeleteTask(s, 3); return err })
+
+	journal := tasks.NewJournaledStore(inner, path, "undo")
+	changes, err := journal.Undo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Action != "delete" {
+		t.Fatalf("undid %+v", changes)
+	}
+	restored, err := inner.Get(3)
+	if err != nil {
+		t.Fatalf("task 3 not restored: %v", err)
+	}
+	if restored.Description != "three" || restored.CreatedAt.IsZero() {
+		t.Fatalf("restored %+v", restored)
+	}
+
+	// The bulk complete is undone as a whole
+	if _, err := journal.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	list, _ := inner.List()
+	for _, task := range list {
+		if task.Completed() {
+			t.Fatalf("task %d still completed", task.ID)
+		}
+	}
+
+	if _, err := journal.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if task, _ := inner.Get(2); !task.Completed() {
+		t.Fatal("redo did not complete task 2")
+	}
+
+	// A new change drops what is left to redo
+	run("edit", func(s tasks.Store) error {
+		description := "renamed"
+		_, err := tasks.EditTask(s, 1, tasks.TaskEdit{Description: &description})
+		return err
+	})
+	if _, err := journal.Redo(); err == nil {
+		t.Fatal("expected nothing to redo")
+	}
+
+	history, err := journal.History()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history[0].Action != "edit" || history[1].Action != "redo complete" {
+		t.Fatalf("got history %+v", history[:2])
+	}
+	if !history[4].Undone || history[4].Action != "delete" {
+		t.Fatalf("delete should be marked undone: %+v", history[4])
+	}
+}
+
+func TestJournalFailedBatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.journal")
+	s := tasks.NewJournaledStore(tasks.NewMemoryStore(tasks.Tasks{ID: 1, Description: "a", CreatedAt: time.Now()}), path, "delete")
+
+	_, err := tasks.DeleteTasks(s, tasks.ListOptions{IDs: []int{1, 2}}, false)
+	if !errors.Is(err, tasks.ErrNotFound) {
+		t.Fatalf("got %v, want ErrNotFound", err)
+	}
+	history, err := s.History()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 0 {
+		t.Fatalf("failed batch journaled: %+v", history)
+	}
+}
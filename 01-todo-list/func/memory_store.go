@@ -0,0 +1,88 @@
+package tasks
+
+import "slices"
+
+// MemoryStore keeps tasks in memory only. It is meant for tests and for
+// code embedding the package that does not need persistence.
+type MemoryStore struct {
+	tasks  []Tasks
+	nextID int
+}
+
+func NewMemoryStore(tasks ...Tasks) *MemoryStore {
+	s := &MemoryStore{nextID: 1}
+	for _, task := range tasks {
+		s.tasks = append(s.tasks, cloneTask(task))
+		s.nextID = max(s.nextID, task.ID+1)
+	}
+	return s
+}
+
+func (s *MemoryStore) List() ([]Tasks, error) {
+	tasks := make([]Tasks, len(s.tasks))
+	for i, task := range s.tasks {
+		tasks[i] = cloneTask(task)
+	}
+	return tasks, nil
+}
+
+func (s *MemoryStore) Get(id int) (Tasks, error) {
+	task, err := findTask(s.tasks, id)
+	return cloneTask(task), err
+}
+
+func (s *MemoryStore) Create(task Tasks) (Tasks, error) {
+	if task.ID == 0 {
+		task.ID = s.nextID
+	} else if _, err := findTask(s.tasks, task.ID); err == nil {
+		return Tasks{}, errTaskExists(task.ID)
+	}
+	s.nextID = max(s.nextID, task.ID+1)
+	s.tasks = append(s.tasks, cloneTask(task))
+	return task, nil
+}
+
+func (s *MemoryStore) Update(task Tasks) error {
+	i := slices.IndexFunc(s.tasks, func(t Tasks) bool { return t.ID == task.ID })
+	if i < 0 {
+		return notFound(task.ID)
+	}
+	s.tasks[i] = cloneTask(task)
+	return nil
+}
+
+func (s *MemoryStore) Delete(id int) error {
+	i := slices.IndexFunc(s.tasks, func(t Tasks) bool { return t.ID == id })
+	if i < 0 {
+		return notFound(id)
+	}
+	s.tasks = slices.Delete(s.tasks, i, i+1)
+	return nil
+}
+
+// Batch runs fn against a copy of the store, keeping its changes only
+// when fn succeeds.
+func (s *MemoryStore) Batch(fn func(tx Store) error) error {
+	tx := &MemoryStore{nextID: s.nextID}
+	for _, task := range s.tasks {
+		tx.tasks = append(tx.tasks, cloneTask(task))
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	s.tasks, s.nextID = tx.tasks, tx.nextID
+	return nil
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// cloneTask copies the slices of a task so callers cannot alias stored data.
+func cloneTask(t Tasks) Tasks {
+	t.Tags = slices.Clone(t.Tags)
+	t.BlockedBy = slices.Clone(t.BlockedBy)
+	t.Transitions = slices.Clone(t.Transitions)
+	t.TimeLog = slices.Clone(t.TimeLog)
+	return t
+}
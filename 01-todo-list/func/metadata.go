@@ -0,0 +1,110 @@
+package tasks
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Priority ranks tasks; the zero value means no priority was set.
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+)
+
+var priorityNames = map[Priority]string{
+	PriorityNone:   "",
+	PriorityLow:    "low",
+	PriorityMedium: "medium",
+	PriorityHigh:   "high",
+}
+
+func (p Priority) String() string {
+	return priorityNames[p]
+}
+
+// ParsePriority accepts high, medium and low or their first letter.
+func ParsePriority(s string) (Priority, error) {
+	value := strings.ToLower(strings.TrimSpace(s))
+	if value == "" {
+		return PriorityNone, nil
+	}
+	for p, name := range priorityNames {
+		if p != PriorityNone && (value == name || value == name[:1]) {
+			return p, nil
+		}
+	}
+	return PriorityNone, fmt.Errorf("invalid priority %q (want high, medium or low)", s)
+}
+
+// ParseDescription pulls +tag and @project words out of a description,
+// returning the remaining text. When several projects are given the last
+// one wins.
+func ParseDescription(text string) (description string, tags []string, project string) {
+	var words []string
+	for _, word := range strings.Fields(text) {
+		switch {
+		case len(word) > 1 && word[0] == '+':
+			tags, _ = addTag(tags, word[1:]) // words hold no spaces
+		case len(word) > 1 && word[0] == '@':
+			project = word[1:]
+		default:
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " "), tags, project
+}
+
+// addTag appends tag unless it is already present. Tags are stored space
+// separated, so ones with whitespace inside are rejected.
+func addTag(tags []string, tag string) ([]string, error) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "+")
+	if strings.ContainsFunc(tag, unicode.IsSpace) {
+		return nil, fmt.Errorf("invalid tag %q: tags cannot contain spaces", tag)
+	}
+	if tag == "" || slices.Contains(tags, tag) {
+		return tags, nil
+	}
+	return append(tags, tag), nil
+}
+
+// HasTag reports whether the task carries the given tag.
+func (t Tasks) HasTag(tag string) bool {
+	return slices.Contains(t.Tags, strings.TrimPrefix(tag, "+"))
+}
+
+// joinTags is the on-disk form of a tag list.
+func joinTags(tags []string) string {
+	return strings.Join(tags, " ")
+}
+
+func splitTags(s string) []string {
+	return strings.Fields(s)
+}
+
+// formatTags shows tags the way they are typed.
+func formatTags(tags []string) string {
+	if len(tags) == 0 {
+		return "-"
+	}
+	return "+" + strings.Join(tags, " +")
+}
+
+func formatProject(project string) string {
+	if project == "" {
+		return "-"
+	}
+	return "@" + project
+}
+
+func formatPriority(p Priority) string {
+	if p == PriorityNone {
+		return "-"
+	}
+	return p.String()
+}
@@ -0,0 +1,783 @@
+package tasks
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats understood by Printer
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputJSONL = "jsonl"
+	OutputCSV   = "csv"
+	OutputYAML  = "yaml"
+)
+
+// Printer writes command results in the chosen output format. The table
+// format is meant for people; the others for scripts and pipelines.
+type Printer struct {
+	Out    io.Writer
+	Format string
+	Color  bool // highlight tables with terminal escape codes
+}
+
+// Validate rejects unknown output formats.
+func (p Printer) Validate() error {
+	switch p.Format {
+	case OutputTable, OutputJSON, OutputJSONL, OutputCSV, OutputYAML:
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q (want table, json, jsonl, csv or yaml)", p.Format)
+}
+
+// taskOutput is the machine-readable form of a task.
+type taskOutput struct {
+	ID          int                `json:"id" yaml:"id"`
+	Description string             `json:"description" yaml:"description"`
+	CreatedAt   string             `json:"created_at" yaml:"created_at"`
+	CompletedAt string             `json:"completed_at,omitempty" yaml:"completed_at,omitempty"`
+	Due         string             `json:"due,omitempty" yaml:"due,omitempty"`
+	Priority    string             `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Tags        []string           `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Project     string             `json:"project,omitempty" yaml:"project,omitempty"`
+	Recur       string             `json:"recur,omitempty" yaml:"recur,omitempty"`
+	Series      int                `json:"series,omitempty" yaml:"series,omitempty"`
+	Parent      int                `json:"parent,omitempty" yaml:"parent,omitempty"`
+	BlockedBy   []int              `json:"blocked_by,omitempty" yaml:"blocked_by,omitempty"`
+	State       string             `json:"state" yaml:"state"`
+	Transitions []transitionOutput `json:"transitions,omitempty" yaml:"transitions,omitempty"`
+	TimeLog     []timeEntryOutput  `json:"time_log,omitempty" yaml:"time_log,omitempty"`
+	UpdatedAt   string             `json:"updated_at,omitempty" yaml:"updated_at,omitempty"`
+}
+
+// timeEntryOutput is the machine-readable form of time spent on a task.
+type timeEntryOutput struct {
+	Start string `json:"start" yaml:"start"`
+	End   string `json:"end,omitempty" yaml:"end,omitempty"`
+}
+
+// transitionOutput is the machine-readable form of a state transition.
+type transitionOutput struct {
+	State string `json:"state" yaml:"state"`
+	At    string `json:"at" yaml:"at"`
+}
+
+func newTaskOutput(t Tasks) taskOutput {
+	return taskOutput{
+		ID:          t.ID,
+		Description: t.Description,
+		CreatedAt:   formatOptionalTime(t.CreatedAt),
+		CompletedAt: formatOptionalTime(t.CompletedAt),
+		Due:         formatOptionalTime(t.Due),
+		Priority:    t.Priority.String(),
+		Tags:        t.Tags,
+		Project:     t.Project,
+		Recur:       t.Recur,
+		Series:      t.Series,
+		Parent:      t.Parent,
+		BlockedBy:   t.BlockedBy,
+		State:       t.WorkflowState(),
+		Transitions: newTransitionOutputs(t.Transitions),
+		TimeLog:     newTimeEntryOutputs(t.TimeLog),
+		UpdatedAt:   formatOptionalTime(t.UpdatedAt),
+	}
+}
+
+func newTimeEntryOutputs(entries []TimeEntry) []timeEntryOutput {
+	var rows []timeEntryOutput
+	for _, e := range entries {
+		rows = append(rows, timeEntryOutput{formatOptionalTime(e.Start), formatOptionalTime(e.End)})
+	}
+	return rows
+}
+
+func newTransitionOutputs(transitions []Transition) []transitionOutput {
+	var rows []transitionOutput
+	for _, t := range transitions {
+		rows = append(rows, transitionOutput{t.State, formatOptionalTime(t.At)})
+	}
+	return rows
+}
+
+// PrintTaskList writes the result of ListTasks. Tables are split by group
+// and their columns follow the status shown: open tasks need no completion
+// column, completed ones show when they were done and mixed lists show each
+// task's status. Blockers and time spent get a column when any task has
+// them, as do the workflow states of open tasks once any was started. Tree
+// lists draw subtasks under their parents. The other formats write every
+// task as a flat list.
+func (p Printer) PrintTaskList(list TaskList) error {
+	if p.Format != OutputTable {
+		return p.printTasks(list.All())
+	}
+
+	columns := []column{
+		idColumn, descriptionColumn, projectColumn, tagsColumn, priorityColumn,
+		createdColumn, dueColumn,
+	}
+	if slices.ContainsFunc(list.All(), func(t Tasks) bool { return len(t.BlockedBy) > 0 }) {
+		columns = append(columns, blockedByColumn)
+	}
+	if slices.ContainsFunc(list.All(), func(t Tasks) bool { return len(t.TimeLog) > 0 }) {
+		columns = append(columns, elapsedColumn)
+	}
+	switch list.Status {
+	case StatusOpen:
+		if slices.ContainsFunc(list.All(), func(t Tasks) bool { return t.State != "" }) {
+			columns = append(columns, stateColumn)
+		}
+	case StatusDone:
+		columns = append(columns, completedColumn)
+	default:
+		columns = append(columns, statusColumn)
+	}
+
+	for i, group := range list.Groups {
+		if group.Name != "" {
+			if i > 0 {
+				fmt.Fprintln(p.Out)
+			}
+			fmt.Fprintln(p.Out, group.Name)
+		}
+		columns := columns
+		if group.prefix != nil {
+			columns = slices.Clone(columns)
+			columns[1] = column{"Description", func(t Tasks) string { return group.prefix[t.ID] + t.Description }}
+		}
+		if err := writeTable(p.Out, group.Tasks, columns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printTasks writes tasks in one of the machine-readable formats.
+func (p Printer) printTasks(tasks []Tasks) error {
+	if p.Format == OutputCSV {
+		return writeCSV(p.Out, tasks)
+	}
+	rows := make([]taskOutput, 0, len(tasks))
+	for _, task := range tasks {
+		rows = append(rows, newTaskOutput(task))
+	}
+	return printRows(p, rows)
+}
+
+// PrintTask writes the task a command acted on. Tables only get the message.
+func (p Printer) PrintTask(task Tasks, message string) error {
+	switch p.Format {
+	case OutputTable:
+		_, err := fmt.Fprintln(p.Out, message)
+		return err
+	case OutputCSV:
+		return writeCSV(p.Out, []Tasks{task})
+	case OutputJSONL:
+		return printRows(p, []taskOutput{newTaskOutput(task)})
+	}
+	return p.printValue(newTaskOutput(task))
+}
+
+// PrintTasks writes the tasks a bulk command acted on. Tables get the
+// message followed by the tasks.
+func (p Printer) PrintTasks(tasks []Tasks, message string) error {
+	if p.Format != OutputTable {
+		return p.printTasks(tasks)
+	}
+	fmt.Fprintln(p.Out, message)
+	if len(tasks) == 0 {
+		return nil
+	}
+	return writeTable(p.Out, tasks, []column{
+		idColumn, descriptionColumn, projectColumn, tagsColumn, priorityColumn,
+		createdColumn, dueColumn, statusColumn,
+	})
+}
+
+// PrintHistory writes the journaled commands, marking undone ones.
+func (p Printer) PrintHistory(items []HistoryItem) error {
+	switch p.Format {
+	case OutputTable:
+		w := tabwriter.NewWriter(p.Out, 0, 0, 1, ' ', tabwriter.Debug)
+		fmt.Fprintln(w, "When\tCommand\tTasks\tUndone")
+		for _, item := range items {
+			undone := ""
+			if item.Undone {
+				undone = "yes"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", timeDiff(item.Time), item.Action, formatIDs(item.Tasks), undone)
+		}
+		return w.Flush()
+	case OutputCSV:
+		w := csv.NewWriter(p.Out)
+		w.Write([]string{"Time", "Action", "Tasks", "Undone"})
+		for _, item := range items {
+			w.Write([]string{item.Time.Format(time.RFC3339), item.Action, formatIDs(item.Tasks), strconv.FormatBool(item.Undone)})
+		}
+		w.Flush()
+		return w.Error()
+	}
+	if items == nil {
+		items = []HistoryItem{}
+	}
+	return printRows(p, items)
+}
+
+// searchOutput is the machine-readable form of a search result.
+type searchOutput struct {
+	taskOutput `yaml:",inline"`
+	Archived   bool     `json:"archived" yaml:"archived"`
+	Score      float64  `json:"score" yaml:"score"`
+	Matches    [][2]int `json:"matches" yaml:"matches,flow"`
+}
+
+// PrintSearchResults writes search results best first. Tables highlight
+// the matching parts of descriptions when Color is set; the other formats
+// give their byte ranges.
+func (p Printer) PrintSearchResults(results []SearchResult) error {
+	switch p.Format {
+	case OutputTable:
+		return writeSearchTable(p.Out, results, p.Color)
+	case OutputCSV:
+		w := csv.NewWriter(p.Out)
+		w.Write(append(slices.Clip(csvHeader), "Archived", "Score"))
+		for _, result := range results {
+			record := taskRecord(result.Task)
+			w.Write(append(record, strconv.FormatBool(result.Archived), strconv.FormatFloat(result.Score, 'f', 3, 64)))
+		}
+		w.Flush()
+		return w.Error()
+	}
+	rows := make([]searchOutput, 0, len(results))
+	for _, result := range results {
+		rows = append(rows, searchOutput{newTaskOutput(result.Task), result.Archived, result.Score, result.Matches})
+	}
+	return printRows(p, rows)
+}
+
+// writeSearchTable lays results out like writeTable. tabwriter would count
+// highlighting escape codes as text, so the widths are measured first and
+// the rows padded by hand.
+func writeSearchTable(out io.Writer, results []SearchResult, color bool) error {
+	columns := []column{idColumn, descriptionColumn, projectColumn, tagsColumn, statusColumn, createdColumn}
+	rows := [][]string{make([]string, len(columns))}
+	for i, c := range columns {
+		rows[0][i] = c.header
+	}
+	for _, result := range results {
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = c.value(result.Task)
+		}
+		if result.Archived {
+			row[4] = "archived"
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(columns))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	var b strings.Builder
+	for r, row := range rows {
+		for i, cell := range row {
+			width := utf8.RuneCountInString(cell)
+			if r > 0 && i == 1 && color {
+				cell = highlight(cell, results[r-1].Matches)
+			}
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-width+1) + "|")
+			}
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// highlight marks the byte ranges of text in bold yellow.
+func highlight(text string, ranges [][2]int) string {
+	var b strings.Builder
+	last := 0
+	for _, r := range ranges {
+		b.WriteString(text[last:r[0]])
+		b.WriteString("\x1b[1;33m" + text[r[0]:r[1]] + "\x1b[0m")
+		last = r[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// PrintNote writes an extra message for people; the machine-readable
+// formats leave it out.
+func (p Printer) PrintNote(message string) {
+	if p.Format == OutputTable {
+		fmt.Fprintln(p.Out, message)
+	}
+}
+
+// PrintLists writes list names, marking the current list.
+func (p Printer) PrintLists(names []string, current string) error {
+	type listOutput struct {
+		Name    string `json:"name" yaml:"name"`
+		Current bool   `json:"current" yaml:"current"`
+	}
+
+	switch p.Format {
+	case OutputTable:
+		for _, name := range names {
+			marker := " "
+			if name == current {
+				marker = "*"
+			}
+			fmt.Fprintln(p.Out, marker, name)
+		}
+		return nil
+	case OutputCSV:
+		w := csv.NewWriter(p.Out)
+		w.Write([]string{"Name", "Current"})
+		for _, name := range names {
+			w.Write([]string{name, strconv.FormatBool(name == current)})
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	rows := make([]listOutput, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, listOutput{Name: name, Current: name == current})
+	}
+	return printRows(p, rows)
+}
+
+// boardOutput is the machine-readable form of a board column.
+type boardOutput struct {
+	State string       `json:"state" yaml:"state"`
+	Tasks []taskOutput `json:"tasks" yaml:"tasks"`
+}
+
+// PrintBoard writes the board. Tables draw the columns side by side,
+// sharing width cells between them; csv lists the tasks and the other
+// formats give each column with its tasks.
+func (p Printer) PrintBoard(board Board, width int) error {
+	switch p.Format {
+	case OutputTable:
+		return p.writeBoard(board, width)
+	case OutputCSV:
+		var all []Tasks
+		for _, column := range board.Columns {
+			all = append(all, column.Tasks...)
+		}
+		return writeCSV(p.Out, all)
+	}
+	rows := make([]boardOutput, 0, len(board.Columns))
+	for _, column := range board.Columns {
+		row := boardOutput{State: column.State, Tasks: []taskOutput{}}
+		for _, task := range column.Tasks {
+			row.Tasks = append(row.Tasks, newTaskOutput(task))
+		}
+		rows = append(rows, row)
+	}
+	return printRows(p, rows)
+}
+
+// writeBoard lays the columns out side by side, each task a card of its
+// ID and description wrapped to the column width.
+func (p Printer) writeBoard(board Board, width int) error {
+	const gap = 3
+	n := len(board.Columns)
+	if n == 0 {
+		return nil
+	}
+	columnWidth := max((width-gap*(n-1))/n, 12)
+
+	cells := make([][]string, n)
+	for i, column := range board.Columns {
+		header := fmt.Sprintf("%s (%d)", strings.ToUpper(column.State), len(column.Tasks))
+		if runes := []rune(header); len(runes) > columnWidth {
+			header = string(runes[:columnWidth-1]) + "…"
+		}
+		cells[i] = append(cells[i], header)
+		cells[i] = append(cells[i], strings.Repeat("─", columnWidth))
+		for _, task := range column.Tasks {
+			cells[i] = append(cells[i], card(task, columnWidth)...)
+		}
+	}
+	height := 0
+	for _, column := range cells {
+		height = max(height, len(column))
+	}
+
+	var b strings.Builder
+	for row := range height {
+		var line strings.Builder
+		for i, column := range cells {
+			cell := ""
+			if row < len(column) {
+				cell = column[row]
+			}
+			padding := columnWidth - utf8.RuneCountInString(cell) + gap
+			if row == 0 && p.Color {
+				cell = "\x1b[1m" + cell + "\x1b[0m"
+			}
+			line.WriteString(cell)
+			if i < n-1 {
+				line.WriteString(strings.Repeat(" ", padding))
+			}
+		}
+		b.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+	}
+	_, err := io.WriteString(p.Out, b.String())
+	return err
+}
+
+// card wraps "#ID description" to width, indenting the lines after the
+// first under the description. Words too long for a line are cut.
+func card(t Tasks, width int) []string {
+	prefix := "#" + strconv.Itoa(t.ID) + " "
+	indent := strings.Repeat(" ", len(prefix))
+	words := strings.Fields(t.Description)
+
+	var lines []string
+	line, fresh := prefix, true // fresh while the line holds no word yet
+	for _, word := range words {
+		if !fresh && utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > width {
+			lines = append(lines, line)
+			line, fresh = indent, true
+		}
+		if !fresh {
+			line += " "
+		}
+		if room := width - utf8.RuneCountInString(line); utf8.RuneCountInString(word) > room {
+			word = string([]rune(word)[:max(room-1, 0)]) + "…"
+		}
+		line += word
+		fresh = false
+	}
+	return append(lines, line)
+}
+
+// reportOutput is the machine-readable form of a time report.
+type reportOutput struct {
+	From    string            `json:"from,omitempty" yaml:"from,omitempty"`
+	To      string            `json:"to" yaml:"to"`
+	Seconds int64             `json:"seconds" yaml:"seconds"`
+	ByTask  []reportRowOutput `json:"by_task,omitempty" yaml:"by_task,omitempty"`
+	ByTag   []reportRowOutput `json:"by_tag,omitempty" yaml:"by_tag,omitempty"`
+	ByDay   []reportRowOutput `json:"by_day,omitempty" yaml:"by_day,omitempty"`
+}
+
+// reportRowOutput is one task, tag or day of a time report.
+type reportRowOutput struct {
+	ID          int    `json:"id,omitempty" yaml:"id,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Tag         string `json:"tag,omitempty" yaml:"tag,omitempty"`
+	Day         string `json:"day,omitempty" yaml:"day,omitempty"`
+	Seconds     int64  `json:"seconds" yaml:"seconds"`
+}
+
+// PrintTimeReport writes the time spent by task, tag and day, leaving out
+// the parts of the report that are nil. Tables round to the minute; the
+// other formats give seconds.
+func (p Printer) PrintTimeReport(report TimeReport) error {
+	seconds := func(d time.Duration) int64 { return int64(d.Round(time.Second) / time.Second) }
+	out := reportOutput{
+		From:    formatOptionalTime(report.From),
+		To:      formatOptionalTime(report.To),
+		Seconds: seconds(report.Total),
+	}
+	for _, row := range report.ByTask {
+		out.ByTask = append(out.ByTask, reportRowOutput{ID: row.Task.ID, Description: row.Task.Description, Seconds: seconds(row.Spent)})
+	}
+	for _, row := range report.ByTag {
+		out.ByTag = append(out.ByTag, reportRowOutput{Tag: row.Name, Seconds: seconds(row.Spent)})
+	}
+	for _, row := range report.ByDay {
+		out.ByDay = append(out.ByDay, reportRowOutput{Day: row.Name, Seconds: seconds(row.Spent)})
+	}
+
+	switch p.Format {
+	case OutputTable:
+		return p.writeTimeReport(report)
+	case OutputCSV:
+		w := csv.NewWriter(p.Out)
+		w.Write([]string{"Group", "Name", "Seconds"})
+		for _, row := range out.ByTask {
+			w.Write([]string{"task", strconv.Itoa(row.ID), strconv.FormatInt(row.Seconds, 10)})
+		}
+		for _, row := range out.ByTag {
+			w.Write([]string{"tag", row.Tag, strconv.FormatInt(row.Seconds, 10)})
+		}
+		for _, row := range out.ByDay {
+			w.Write([]string{"day", row.Day, strconv.FormatInt(row.Seconds, 10)})
+		}
+		w.Flush()
+		return w.Error()
+	case OutputJSONL:
+		return printRows(p, []reportOutput{out})
+	}
+	return p.printValue(out)
+}
+
+func (p Printer) writeTimeReport(report TimeReport) error {
+	switch {
+	case report.From.IsZero():
+		fmt.Fprintf(p.Out, "Time spent: %s\n", FormatSpent(report.Total))
+	default:
+		fmt.Fprintf(p.Out, "Time spent since %s: %s\n", report.From.Format("Mon 2006-01-02"), FormatSpent(report.Total))
+	}
+
+	sections := []struct {
+		title, header string
+		rows          []TimeRow
+		name          func(TimeRow) string
+	}{
+		{"By task", "ID\tDescription", report.ByTask, func(r TimeRow) string { return strconv.Itoa(r.Task.ID) + "\t" + r.Task.Description }},
+		{"By tag", "Tag", report.ByTag, func(r TimeRow) string {
+			if r.Name == "-" {
+				return r.Name // untagged
+			}
+			return formatTags([]string{r.Name})
+		}},
+		{"By day", "Day", report.ByDay, func(r TimeRow) string { return r.Day.Format("Mon 2006-01-02") }},
+	}
+	for _, section := range sections {
+		if len(section.rows) == 0 {
+			continue
+		}
+		fmt.Fprintf(p.Out, "\n%s\n", section.title)
+		w := tabwriter.NewWriter(p.Out, 0, 0, 1, ' ', tabwriter.Debug)
+		fmt.Fprintln(w, section.header+"\tSpent")
+		for _, row := range section.rows {
+			fmt.Fprintf(w, "%s\t%s\n", section.name(row), FormatSpent(row.Spent))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintDoctorReport writes the problems found in a data file. csv and jsonl
+// get one row per problem, json and yaml the whole report.
+func (p Printer) PrintDoctorReport(report DoctorReport) error {
+	switch p.Format {
+	case OutputTable:
+		for _, problem := range report.Problems {
+			fmt.Fprintf(p.Out, "line %d: %s: %s\n", problem.Line, problem.Field, problem.Message)
+		}
+		if len(report.Problems) == 0 {
+			fmt.Fprintf(p.Out, "%s: no problems found in %d tasks\n", report.Path, report.Tasks)
+		} else {
+			fmt.Fprintf(p.Out, "%s: %d problems found\n", report.Path, len(report.Problems))
+		}
+		if report.Backup != "" {
+			fmt.Fprintf(p.Out, "Repaired file written with %d tasks, original saved to %s\n", report.Tasks, report.Backup)
+		}
+		return nil
+	case OutputCSV:
+		w := csv.NewWriter(p.Out)
+		w.Write([]string{"Line", "Field", "Message"})
+		for _, problem := range report.Problems {
+			w.Write([]string{strconv.Itoa(problem.Line), problem.Field, problem.Message})
+		}
+		w.Flush()
+		return w.Error()
+	case OutputJSONL:
+		return printRows(p, report.Problems)
+	}
+	if report.Problems == nil {
+		report.Problems = []Problem{}
+	}
+	return p.printValue(report)
+}
+
+// syncOutput is the machine-readable form of a sync.
+type syncOutput struct {
+	Remote     string           `json:"remote" yaml:"remote"`
+	Pulled     int              `json:"pulled" yaml:"pulled"`
+	Pushed     int              `json:"pushed" yaml:"pushed"`
+	Conflicts  []int            `json:"conflicts" yaml:"conflicts"`
+	Renumbered []renumberOutput `json:"renumbered,omitempty" yaml:"renumbered,omitempty"`
+	Committed  bool             `json:"committed" yaml:"committed"`
+}
+
+type renumberOutput struct {
+	From int `json:"from" yaml:"from"`
+	To   int `json:"to" yaml:"to"`
+}
+
+// PrintSyncResult writes what a sync pulled and pushed, and the tasks it
+// moved to new IDs.
+func (p Printer) PrintSyncResult(result SyncResult) error {
+	out := syncOutput{
+		Remote:    result.Remote,
+		Pulled:    result.Pulled,
+		Pushed:    result.Pushed,
+		Conflicts: []int{},
+		Committed: result.Committed,
+	}
+	for _, c := range result.Conflicts {
+		out.Conflicts = append(out.Conflicts, c.ID)
+	}
+	for from, to := range result.Renumbered {
+		out.Renumbered = append(out.Renumbered, renumberOutput{From: from, To: to})
+	}
+	slices.SortFunc(out.Renumbered, func(a, b renumberOutput) int { return cmp.Compare(a.From, b.From) })
+
+	switch p.Format {
+	case OutputTable:
+		if out.Pulled == 0 && out.Pushed == 0 && !out.Committed {
+			_, err := fmt.Fprintf(p.Out, "Already in sync with %s\n", out.Remote)
+			return err
+		}
+		fmt.Fprintf(p.Out, "Synced with %s: %d pulled, %d pushed", out.Remote, out.Pulled, out.Pushed)
+		switch len(out.Conflicts) {
+		case 0:
+		case 1:
+			fmt.Fprint(p.Out, ", 1 conflict resolved")
+		default:
+			fmt.Fprintf(p.Out, ", %d conflicts resolved", len(out.Conflicts))
+		}
+		fmt.Fprintln(p.Out)
+		for _, r := range out.Renumbered {
+			fmt.Fprintf(p.Out, "Task %d was also added elsewhere, yours is now task %d\n", r.From, r.To)
+		}
+		return nil
+	case OutputCSV:
+		w := csv.NewWriter(p.Out)
+		w.Write([]string{"Remote", "Pulled", "Pushed", "Conflicts", "Committed"})
+		w.Write([]string{out.Remote, strconv.Itoa(out.Pulled), strconv.Itoa(out.Pushed), strconv.Itoa(len(out.Conflicts)), strconv.FormatBool(out.Committed)})
+		w.Flush()
+		return w.Error()
+	case OutputJSONL:
+		return printRows(p, []syncOutput{out})
+	}
+	return p.printValue(out)
+}
+
+// printRows writes a slice of records: one JSON value per line for jsonl,
+// a single document otherwise.
+func printRows[T any](p Printer, rows []T) error {
+	if p.Format != OutputJSONL {
+		return p.printValue(rows)
+	}
+	enc := json.NewEncoder(p.Out)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p Printer) printValue(v any) error {
+	switch p.Format {
+	case OutputYAML:
+		enc := yaml.NewEncoder(p.Out)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		enc := json.NewEncoder(p.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+}
+
+// writeCSV writes tasks with the same columns as the CSV store.
+func writeCSV(out io.Writer, tasks []Tasks) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if err := w.Write(taskRecord(task)); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// column is a single column of the task tables.
+type column struct {
+	header string
+	value  func(Tasks) string
+}
+
+var (
+	idColumn          = column{"ID", func(t Tasks) string { return strconv.Itoa(t.ID) }}
+	descriptionColumn = column{"Description", func(t Tasks) string { return t.Description }}
+	projectColumn     = column{"Project", func(t Tasks) string { return formatProject(t.Project) }}
+	tagsColumn        = column{"Tags", func(t Tasks) string { return formatTags(t.Tags) }}
+	priorityColumn    = column{"Priority", func(t Tasks) string { return formatPriority(t.Priority) }}
+	createdColumn     = column{"Created At", func(t Tasks) string { return timeDiff(t.CreatedAt) }}
+	dueColumn         = column{"Due", func(t Tasks) string { return formatRecurringDue(t) }}
+	statusColumn      = column{"Status", Tasks.status}
+	completedColumn   = column{"Completed", func(t Tasks) string { return timeDiff(t.CompletedAt) }}
+	blockedByColumn   = column{"Blocked By", func(t Tasks) string { return formatIDs(t.BlockedBy) }}
+	stateColumn       = column{"State", Tasks.WorkflowState}
+	elapsedColumn     = column{"Elapsed", formatElapsed}
+)
+
+// formatElapsed shows the time spent on a task, marking a running timer.
+func formatElapsed(t Tasks) string {
+	if len(t.TimeLog) == 0 {
+		return "-"
+	}
+	elapsed := FormatSpent(t.Elapsed(time.Now()))
+	if t.Tracking() {
+		elapsed += " (running)"
+	}
+	return elapsed
+}
+
+// formatIDs lists task IDs as "3, 5, 7".
+func formatIDs(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func writeTable(out io.Writer, tasks []Tasks, columns []column) error {
+	// Create a new tabwriter
+	w := tabwriter.NewWriter(out, 0, 0, 1, ' ', tabwriter.Debug)
+
+	// show the header
+	headers := make([]string, len(columns))
+	for i, c := range columns {
+		headers[i] = c.header
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+
+	// Write the records to the writer
+	for _, task := range tasks {
+		values := make([]string, len(columns))
+		for i, c := range columns {
+			values[i] = c.value(task)
+		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,78 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// DefaultList is the list used when no --list is given.
+const DefaultList = "default"
+
+// storeExt is the file extension each backend uses for a named list.
+var storeExt = map[string]string{
+	StoreCSV:    ".csv",
+	StoreSQLite: ".sqlite",
+}
+
+// DataDir returns the directory holding named lists, following the XDG base
+// directory spec ($XDG_DATA_HOME/tasks, falling back to ~/.local/share/tasks).
+func DataDir() (string, error) {
+	base := os.Getenv("XDG_DATA_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find home directory: %w", err)
+		}
+		base = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(base, "tasks"), nil
+}
+
+// ListPath returns the data file of the named list for the given backend,
+// creating the data directory if needed.
+func ListPath(kind, list string) (string, error) {
+	ext, ok := storeExt[kind]
+	if !ok {
+		return "", fmt.Errorf("unknown store %q", kind)
+	}
+	if list == "" || list != filepath.Base(list) || strings.HasPrefix(list, ".") {
+		return "", fmt.Errorf("invalid list name %q", list)
+	}
+
+	dir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create data directory: %w", err)
+	}
+	return filepath.Join(dir, list+ext), nil
+}
+
+// Lists returns the names of all lists stored with the given backend.
+func Lists(kind string) ([]string, error) {
+	dir, err := DataDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), storeExt[kind])
+		if ok && !entry.IsDir() && name != "" {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
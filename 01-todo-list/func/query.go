@@ -0,0 +1,287 @@
+package tasks
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Statuses accepted by ListOptions.Status and the status: query field
+const (
+	StatusOpen = "open"
+	StatusDone = "done"
+	StatusAll  = "all"
+)
+
+// Query is a parsed list query such as
+//
+//	status:open due<7d tag:ops -project:web sort:-priority
+//
+// Terms are separated by spaces and all of them must match. A term is
+// field, operator (: = < > <= >=) and value; a leading - negates it. Words
+// without a field match the description. Dates are absolute ("2026-11-01"),
+// natural ("friday"), ahead of now ("7d") or behind it ("-7d").
+type Query struct {
+	Status  string // status: value, empty when not given
+	GroupBy string // group: value
+	filters []func(Tasks) bool
+	sorts   []string
+}
+
+// ParseQuery parses a query, resolving relative dates against now.
+func ParseQuery(s string, now time.Time) (Query, error) {
+	var q Query
+	for _, term := range strings.Fields(s) {
+		negate := false
+		if len(term) > 1 && term[0] == '-' {
+			negate = true
+			term = term[1:]
+		}
+
+		field, op, value, ok := splitTerm(term)
+		if !ok {
+			// A plain word searches the description
+			word := strings.ToLower(term)
+			q.addFilter(negate, func(t Tasks) bool {
+				return strings.Contains(strings.ToLower(t.Description), word)
+			})
+			continue
+		}
+
+		var (
+			match func(Tasks) bool
+			err   error
+		)
+		field = strings.ToLower(field)
+		switch field {
+		case "status", "is", "sort", "group":
+			// These set how the query lists rather than filter tasks
+			if negate {
+				return Query{}, fmt.Errorf("%s cannot be negated in %q", field, "-"+term)
+			}
+		}
+		switch field {
+		case "status", "is":
+			match, err = q.parseStatus(value, now)
+		case "sort":
+			for _, key := range strings.Split(value, ",") {
+				if _, err := sortFunc(key); err != nil {
+					return Query{}, err
+				}
+				q.sorts = append(q.sorts, key)
+			}
+			continue
+		case "group":
+			q.GroupBy = value
+			continue
+		case "tag":
+			match = func(t Tasks) bool { return t.HasTag(value) }
+		case "state":
+			state := strings.ToLower(value)
+			match = func(t Tasks) bool { return t.WorkflowState() == state }
+		case "project":
+			project := strings.TrimPrefix(value, "@")
+			match = func(t Tasks) bool { return t.Project == project }
+		case "priority":
+			match, err = comparePriority(op, value)
+		case "id":
+			match, err = compareID(op, value)
+		case "desc", "description":
+			word := strings.ToLower(value)
+			match = func(t Tasks) bool { return strings.Contains(strings.ToLower(t.Description), word) }
+		case "due":
+			match, err = compareTime(op, value, now, func(t Tasks) time.Time { return t.Due })
+		case "created":
+			match, err = compareTime(op, value, now, func(t Tasks) time.Time { return t.CreatedAt })
+		case "completed", "done":
+			match, err = compareTime(op, value, now, func(t Tasks) time.Time { return t.CompletedAt })
+		default:
+			return Query{}, fmt.Errorf("unknown query field %q", field)
+		}
+		if err != nil {
+			return Query{}, err
+		}
+		if match != nil {
+			q.addFilter(negate, match)
+		}
+	}
+	return q, nil
+}
+
+// splitTerm splits "due<=7d" into its field, operator and value.
+func splitTerm(term string) (field, op, value string, ok bool) {
+	i := strings.IndexAny(term, ":=<>")
+	if i <= 0 {
+		return "", "", "", false
+	}
+	field, rest := term[:i], term[i:]
+	for _, candidate := range []string{"<=", ">=", ":", "=", "<", ">"} {
+		if value, ok := strings.CutPrefix(rest, candidate); ok {
+			return field, candidate, value, true
+		}
+	}
+	return "", "", "", false
+}
+
+func (q *Query) addFilter(negate bool, match func(Tasks) bool) {
+	if negate {
+		q.filters = append(q.filters, func(t Tasks) bool { return !match(t) })
+		return
+	}
+	q.filters = append(q.filters, match)
+}
+
+// parseStatus records open, done and all as the query status; overdue is
+// open plus a due date filter.
+func (q *Query) parseStatus(value string, now time.Time) (func(Tasks) bool, error) {
+	switch strings.ToLower(value) {
+	case StatusOpen, "todo":
+		q.Status = StatusOpen
+	case StatusDone, "completed":
+		q.Status = StatusDone
+	case StatusAll:
+		q.Status = StatusAll
+	case "overdue":
+		q.Status = StatusOpen
+		return func(t Tasks) bool { return t.Overdue(now) }, nil
+	default:
+		return nil, fmt.Errorf("invalid status %q (want open, done, all or overdue)", value)
+	}
+	return nil, nil
+}
+
+// Match reports whether the task passes every filter of the query.
+func (q Query) Match(t Tasks) bool {
+	for _, match := range q.filters {
+		if !match(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// compareOp applies a comparison operator to the result of cmp.Compare.
+func compareOp(op string, c int) bool {
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return c == 0
+}
+
+func comparePriority(op, value string) (func(Tasks) bool, error) {
+	want := PriorityNone
+	if !strings.EqualFold(value, "none") {
+		var err error
+		if want, err = ParsePriority(value); err != nil {
+			return nil, err
+		}
+	}
+	return func(t Tasks) bool { return compareOp(op, cmp.Compare(t.Priority, want)) }, nil
+}
+
+func compareID(op, value string) (func(Tasks) bool, error) {
+	want, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id %q", value)
+	}
+	return func(t Tasks) bool { return compareOp(op, cmp.Compare(t.ID, want)) }, nil
+}
+
+// compareTime compares a task date with a query date. ":" and "=" match the
+// same calendar day; "none" and "any" test whether the date is set at all.
+func compareTime(op, value string, now time.Time, get func(Tasks) time.Time) (func(Tasks) bool, error) {
+	switch strings.ToLower(value) {
+	case "none":
+		return func(t Tasks) bool { return get(t).IsZero() }, nil
+	case "any":
+		return func(t Tasks) bool { return !get(t).IsZero() }, nil
+	}
+
+	want, err := parseQueryTime(value, now)
+	if err != nil {
+		return nil, err
+	}
+	return func(t Tasks) bool {
+		got := get(t)
+		if got.IsZero() {
+			return false
+		}
+		if op == ":" || op == "=" {
+			gy, gm, gd := got.In(want.Location()).Date()
+			wy, wm, wd := want.Date()
+			return gy == wy && gm == wm && gd == wd
+		}
+		return compareOp(op, got.Compare(want))
+	}, nil
+}
+
+// parseQueryTime reads "-7d" as seven days ago and anything else as a due date.
+func parseQueryTime(value string, now time.Time) (time.Time, error) {
+	if ago, ok := strings.CutPrefix(value, "-"); ok {
+		if d, err := ParseDuration(ago); err == nil {
+			return now.Add(-d), nil
+		}
+	}
+	return ParseDue(value, now)
+}
+
+// sortFunc returns the comparison for a sort key; a leading - reverses it.
+func sortFunc(key string) (func(a, b Tasks) int, error) {
+	desc := strings.HasPrefix(key, "-")
+	var compare func(a, b Tasks) int
+	switch strings.TrimPrefix(key, "-") {
+	case SortByID:
+		compare = func(a, b Tasks) int { return cmp.Compare(a.ID, b.ID) }
+	case SortByCreated:
+		compare = func(a, b Tasks) int { return a.CreatedAt.Compare(b.CreatedAt) }
+	case SortByDue:
+		compare = compareDue
+	case SortByPriority:
+		compare = func(a, b Tasks) int { return cmp.Compare(a.Priority, b.Priority) }
+	case "completed":
+		compare = func(a, b Tasks) int { return a.CompletedAt.Compare(b.CompletedAt) }
+	case "description":
+		compare = func(a, b Tasks) int {
+			return cmp.Compare(strings.ToLower(a.Description), strings.ToLower(b.Description))
+		}
+	case "project":
+		compare = func(a, b Tasks) int { return cmp.Compare(a.Project, b.Project) }
+	default:
+		return nil, fmt.Errorf("cannot sort by %q (want id, created, due, priority, completed, description or project)", key)
+	}
+	if desc {
+		return func(a, b Tasks) int { return compare(b, a) }, nil
+	}
+	return compare, nil
+}
+
+// sortTasks orders tasks by each key in turn, ties falling through to the next.
+func sortTasks(tasks []Tasks, keys []string) error {
+	var compares []func(a, b Tasks) int
+	for _, key := range keys {
+		compare, err := sortFunc(key)
+		if err != nil {
+			return err
+		}
+		compares = append(compares, compare)
+	}
+	slices.SortStableFunc(tasks, func(a, b Tasks) int {
+		for _, compare := range compares {
+			if c := compare(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return nil
+}
@@ -0,0 +1,64 @@
+package tasks_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+)
+
+func TestQueryMatch(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	list := []tasks.Tasks{
+		{ID: 1, Description: "Fix login", Tags: []string{"bug"}, Project: "web", Priority: tasks.PriorityHigh, Due: now.Add(48 * time.Hour)},
+		{ID: 2, Description: "Rotate keys", Tags: []string{"ops"}, Priority: tasks.PriorityMedium, Due: now.Add(-time.Hour)},
+		{ID: 3, Description: "Patch servers", Tags: []string{"ops"}, Project: "infra", Due: now.Add(30 * 24 * time.Hour)},
+		{ID: 4, Description: "Write docs", CompletedAt: now.Add(-time.Hour)},
+	}
+
+	cases := map[string][]int{
+		"":                         {1, 2, 3, 4},
+		"tag:ops":                  {2, 3},
+		"-tag:ops":                 {1, 4},
+		"due<7d":                   {1, 2},
+		"tag:ops due<7d":           {2},
+		"priority>=medium":         {1, 2},
+		"priority:none":            {3, 4},
+		"project:@infra":           {3},
+		"LOGIN":                    {1},
+		"status:overdue":           {2},
+		"due:none":                 {4},
+		"completed>-1d":            {4},
+		"id>1 id<=3":               {2, 3},
+		"desc:keys sort:-priority": {2},
+	}
+	for input, want := range cases {
+		q, err := tasks.ParseQuery(input, now)
+		if err != nil {
+			t.Errorf("ParseQuery(%q): %v", input, err)
+			continue
+		}
+		var got []int
+		for _, task := range list {
+			if q.Match(task) {
+				got = append(got, task.ID)
+			}
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("query %q matched %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	for _, input := range []string{
+		"colour:red", "status:maybe", "priority:urgent", "due<someday", "sort:size",
+		// Terms that set how tasks are listed cannot be negated
+		"-status:done", "-is:open", "-sort:due", "-group:project",
+	} {
+		if _, err := tasks.ParseQuery(input, time.Now()); err == nil {
+			t.Errorf("ParseQuery(%q): expected error", input)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package tasks
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence is a parsed recurrence rule. Rules are a period ("day",
+// "week", "month", "year", "2 weeks", "10d"), a list of weekdays
+// ("mon,thu") or a cron-like spec of minute, hour, day of month, month and
+// day of week ("0 9 * * 1-5").
+type Recurrence struct {
+	rule     string
+	days     int // fixed step in days
+	months   int // fixed step in months
+	weekdays []time.Weekday
+	cron     *cronSpec
+}
+
+// ParseRecurrence parses a recurrence rule.
+func ParseRecurrence(s string) (Recurrence, error) {
+	value := strings.ToLower(strings.TrimSpace(s))
+	r := Recurrence{rule: value}
+
+	switch value {
+	case "":
+		return Recurrence{}, fmt.Errorf("empty recurrence")
+	case "day", "daily":
+		r.days = 1
+		return r, nil
+	case "week", "weekly":
+		r.days = 7
+		return r, nil
+	case "month", "monthly":
+		r.months = 1
+		return r, nil
+	case "year", "yearly":
+		r.months = 12
+		return r, nil
+	}
+
+	if fields := strings.Fields(value); len(fields) == 5 {
+		spec, err := parseCron(fields)
+		if err != nil {
+			return Recurrence{}, fmt.Errorf("invalid recurrence %q: %w", s, err)
+		}
+		r.cron = spec
+		return r, nil
+	}
+
+	if n, unit, ok := strings.Cut(value, " "); ok && strings.TrimSuffix(unit, "s") == "month" {
+		months, err := strconv.Atoi(n)
+		if err != nil || months < 1 {
+			return Recurrence{}, fmt.Errorf("invalid recurrence %q", s)
+		}
+		r.months = months
+		return r, nil
+	}
+	if d, err := ParseDuration(value); err == nil {
+		if d < 24*time.Hour || d%(24*time.Hour) != 0 {
+			return Recurrence{}, fmt.Errorf("invalid recurrence %q: periods must be whole days", s)
+		}
+		r.days = int(d / (24 * time.Hour))
+		return r, nil
+	}
+
+	for _, name := range strings.Split(value, ",") {
+		day, ok := parseWeekday(strings.TrimSpace(name))
+		if !ok {
+			return Recurrence{}, fmt.Errorf("invalid recurrence %q (want day, week, month, year, a period such as 2w, weekdays such as mon,thu or a cron spec)", s)
+		}
+		if !slices.Contains(r.weekdays, day) {
+			r.weekdays = append(r.weekdays, day)
+		}
+	}
+	return r, nil
+}
+
+func (r Recurrence) String() string {
+	return r.rule
+}
+
+// Next returns the due date of the occurrence following one due at due,
+// skipping any that would already be past at now. A task without a due
+// date recurs from now; its days end at midnight.
+func (r Recurrence) Next(due, now time.Time) time.Time {
+	if r.cron != nil {
+		return r.cron.next(latest(due, now))
+	}
+
+	base := due
+	if base.IsZero() {
+		base = endOfDay(now)
+		if len(r.weekdays) == 0 {
+			// Periods count from today, so "day" is due tomorrow
+			now = base
+		}
+	}
+
+	if len(r.weekdays) > 0 {
+		// Keep the time of day of the task, starting no earlier than today
+		start := base
+		if start.Before(now) {
+			y, m, d := now.Date()
+			start = time.Date(y, m, d, base.Hour(), base.Minute(), base.Second(), 0, base.Location())
+		}
+		for i := 0; ; i++ {
+			next := start.AddDate(0, 0, i)
+			if next.After(base) && next.After(now) && slices.Contains(r.weekdays, next.Weekday()) {
+				return next
+			}
+		}
+	}
+	for k := 1; ; k++ {
+		next := addMonths(base, k*r.months).AddDate(0, 0, k*r.days)
+		if next.After(now) {
+			return next
+		}
+	}
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// addMonths moves t by n months, clamping to the end of shorter months so
+// that a task due on the 31st stays at the end of the month.
+func addMonths(t time.Time, n int) time.Time {
+	if n == 0 {
+		return t
+	}
+	y, m, d := t.Date()
+	first := time.Date(y, m+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(d, last)-1)
+}
+
+// cronSpec is a parsed five field cron expression.
+type cronSpec struct {
+	minute, hour, dom, month, dow []bool
+	anyDOM, anyDOW                bool
+}
+
+func parseCron(fields []string) (*cronSpec, error) {
+	var (
+		spec cronSpec
+		err  error
+	)
+	if spec.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("minute: %w", err)
+	}
+	if spec.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("hour: %w", err)
+	}
+	if spec.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("day of month: %w", err)
+	}
+	if spec.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+	if spec.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("day of week: %w", err)
+	}
+	spec.dow[0] = spec.dow[0] || spec.dow[7] // 7 is sunday too
+	spec.anyDOM = fields[2] == "*"
+	spec.anyDOW = fields[4] == "*"
+	return &spec, nil
+}
+
+// parseCronField reads "*", "5", "1-5", "*/15", "1-10/2" and comma
+// separated lists of those. Days of the week may be named.
+func parseCronField(field string, lo, hi int) ([]bool, error) {
+	set := make([]bool, hi+1)
+	for _, part := range strings.Split(field, ",") {
+		part, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepText); err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step %q", stepText)
+			}
+		}
+
+		from, to := lo, hi
+		if part != "*" {
+			first, last, isRange := strings.Cut(part, "-")
+			var err error
+			if from, err = cronValue(first, hi); err != nil {
+				return nil, err
+			}
+			to = from
+			if isRange {
+				if to, err = cronValue(last, hi); err != nil {
+					return nil, err
+				}
+			} else if hasStep {
+				to = hi
+			}
+		}
+		if from < lo || to > hi || from > to {
+			return nil, fmt.Errorf("%q is out of range %d-%d", part, lo, hi)
+		}
+		for v := from; v <= to; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+func cronValue(s string, hi int) (int, error) {
+	if hi == 7 {
+		if day, ok := parseWeekday(s); ok {
+			return int(day), nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return v, nil
+}
+
+// matchesDay follows cron: when both day fields are restricted, either
+// one matching is enough.
+func (c *cronSpec) matchesDay(t time.Time) bool {
+	if !c.month[t.Month()] {
+		return false
+	}
+	dom, dow := c.dom[t.Day()], c.dow[t.Weekday()]
+	switch {
+	case c.anyDOM && c.anyDOW:
+		return true
+	case c.anyDOM:
+		return dow
+	case c.anyDOW:
+		return dom
+	}
+	return dom || dow
+}
+
+// next returns the first matching minute after t. Specs that never match,
+// such as "0 0 31 2 *", give the zero time.
+func (c *cronSpec) next(t time.Time) time.Time {
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	for i := 0; i < 366*5; i++ {
+		if c.matchesDay(day) {
+			for h := 0; h < 24; h++ {
+				if !c.hour[h] {
+					continue
+				}
+				for mn := 0; mn < 60; mn++ {
+					if !c.minute[mn] {
+						continue
+					}
+					at := time.Date(day.Year(), day.Month(), day.Day(), h, mn, 0, 0, day.Location())
+					if at.After(t) {
+						return at
+					}
+				}
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return time.Time{}
+}
+
+// formatRecurringDue shows the due date followed by the recurrence rule.
+func formatRecurringDue(t Tasks) string {
+	if t.Recur == "" {
+		return formatDue(t.Due)
+	}
+	if t.Due.IsZero() {
+		return "every " + t.Recur
+	}
+	return formatDue(t.Due) + " every " + t.Recur
+}
+
+// nextOccurrence returns the task that follows a recurring task completed
+// at now: a fresh copy due at the next date, linked to the same series.
+func nextOccurrence(t Tasks, now time.Time) (Tasks, bool, error) {
+	if t.Recur == "" {
+		return Tasks{}, false, nil
+	}
+	r, err := ParseRecurrence(t.Recur)
+	if err != nil {
+		return Tasks{}, false, err
+	}
+	due := r.Next(t.Due, now)
+	if due.IsZero() {
+		return Tasks{}, false, nil
+	}
+
+	next := cloneTask(t)
+	next.ID = 0
+	next.CreatedAt = now
+	next.CompletedAt = time.Time{}
+	next.State, next.Transitions, next.TimeLog = "", nil, nil
+	next.Due = due
+	if next.Series == 0 {
+		next.Series = t.ID
+	}
+	return next, true, nil
+}
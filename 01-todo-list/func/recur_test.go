@@ -0,0 +1,98 @@
+package tasks_test
+
+import (
+	"testing"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+)
+
+func TestRecurrenceNext(t *testing.T) {
+	// Wednesday afternoon
+	now := time.Date(2026, 10, 14, 15, 0, 0, 0, time.UTC)
+	at := func(m time.Month, d, h, min int) time.Time {
+		return time.Date(2026, m, d, h, min, 0, 0, time.UTC)
+	}
+	endOf := func(m time.Month, d int) time.Time {
+		return time.Date(2026, m, d, 23, 59, 59, 0, time.UTC)
+	}
+
+	cases := []struct {
+		rule string
+		due  time.Time
+		want time.Time
+	}{
+		{"day", time.Time{}, endOf(10, 15)},
+		{"week", endOf(10, 14), endOf(10, 21)},
+		// Occurrences missed while the task was overdue are skipped
+		{"week", endOf(9, 23), endOf(10, 14)},
+		{"2w", endOf(10, 10), endOf(10, 24)},
+		{"month", endOf(10, 31), endOf(11, 30)},
+		{"3 months", endOf(11, 30), time.Date(2027, 2, 28, 23, 59, 59, 0, time.UTC)},
+		{"mon,thu", endOf(10, 12), endOf(10, 15)},
+		{"mon,thu", endOf(10, 15), endOf(10, 19)},
+		{"fri", at(10, 9, 8, 0), at(10, 16, 8, 0)},
+		{"30 9 * * mon-fri", at(10, 14, 9, 30), at(10, 15, 9, 30)},
+		{"0 */6 * * *", time.Time{}, at(10, 14, 18, 0)},
+		{"0 0 1,15 * *", endOf(10, 20), at(11, 1, 0, 0)},
+	}
+	for _, c := range cases {
+		r, err := tasks.ParseRecurrence(c.rule)
+		if err != nil {
+			t.Errorf("ParseRecurrence(%q): %v", c.rule, err)
+			continue
+		}
+		if got := r.Next(c.due, now); !got.Equal(c.want) {
+			t.Errorf("%q after %v: got %v, want %v", c.rule, c.due, got, c.want)
+		}
+	}
+
+	for _, bad := range []string{"", "fortnightly", "12h", "61 * * * *", "mon,funday"} {
+		if _, err := tasks.ParseRecurrence(bad); err == nil {
+			t.Errorf("ParseRecurrence(%q): expected error", bad)
+		}
+	}
+}
+
+func TestCompleteRecurringTask(t *testing.T) {
+	due := time.Now().AddDate(0, 0, 1)
+	s := tasks.NewMemoryStore(tasks.Tasks{
+		ID: 1, Description: "Water plants", CreatedAt: time.Now(),
+		Due: due, Recur: "week", Tags: []string{"home"},
+	})
+
+	done, next, err := tasks.CompleteTasks(s, tasks.ListOptions{IDs: []int{1}}, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != 1 || len(next) != 1 {
+		t.Fatalf("got %d done, %d next", len(done), len(next))
+	}
+	spawned := next[0]
+	if spawned.ID != 2 || spawned.Series != 1 || spawned.Completed() || spawned.Recur != "week" {
+		t.Fatalf("next occurrence %+v", spawned)
+	}
+	if !spawned.Due.Equal(due.AddDate(0, 0, 7)) {
+		t.Fatalf("next due %v, want %v", spawned.Due, due.AddDate(0, 0, 7))
+	}
+
+	// The series keeps pointing at its first task
+	if _, err := tasks.CompleteTask(s, 2); err != nil {
+		t.Fatal(err)
+	}
+	third, err := s.Get(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.Series != 1 || len(third.Tags) != 1 {
+		t.Fatalf("third occurrence %+v", third)
+	}
+
+	// Completing a done task again spawns nothing
+	if _, err := tasks.CompleteTask(s, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(4); err == nil {
+		t.Fatal("completing twice spawned another occurrence")
+	}
+}
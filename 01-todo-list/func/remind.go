@@ -0,0 +1,291 @@
+package tasks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"slices"
+	"time"
+)
+
+// Reminder is a notice that a task is due soon, sent Lead before its due
+// date.
+type Reminder struct {
+	Task Tasks
+	Lead time.Duration
+}
+
+// Title is a one-line summary such as "Task 3 is due in 1 hour".
+func (r Reminder) Title() string {
+	if r.Task.Due.Before(time.Now()) {
+		return fmt.Sprintf("Task %d was due %s", r.Task.ID, timeDiff(r.Task.Due))
+	}
+	return fmt.Sprintf("Task %d is due %s", r.Task.ID, timeDiff(r.Task.Due))
+}
+
+// Notifier delivers reminders.
+type Notifier interface {
+	Notify(r Reminder) error
+}
+
+// WriterNotifier prints reminders, one line each, for terminals and logs.
+type WriterNotifier struct {
+	Out io.Writer
+}
+
+func (n WriterNotifier) Notify(r Reminder) error {
+	_, err := fmt.Fprintf(n.Out, "%s %s: %s\n", time.Now().Format("15:04"), r.Title(), r.Task.Description)
+	return err
+}
+
+// NotifySend shows reminders as desktop notifications through notify-send.
+type NotifySend struct{}
+
+func (NotifySend) Notify(r Reminder) error {
+	out, err := exec.Command("notify-send", "--app-name=tasks", r.Title(), r.Task.Description).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("notify-send: %w: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// WebhookNotifier posts reminders as JSON to a URL on this machine.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client // webhookClient when nil
+}
+
+// webhookClient gives up on endpoints that hang, so they cannot stall the
+// daemon, and refuses redirects that would take reminders off the machine.
+var webhookClient = &http.Client{
+	Timeout: 10 * time.Second,
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if !isLoopback(req.URL.Hostname()) {
+			return fmt.Errorf("redirect to %s leaves this machine", req.URL.Host)
+		}
+		if len(via) >= 10 {
+			return errors.New("too many redirects")
+		}
+		return nil
+	},
+}
+
+func isLoopback(host string) bool {
+	ip := net.ParseIP(host)
+	return host == "localhost" || ip != nil && ip.IsLoopback()
+}
+
+// NewWebhookNotifier checks that rawURL is an http URL on this machine;
+// reminders are not meant to leave it.
+func NewWebhookNotifier(rawURL string) (*WebhookNotifier, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid webhook URL %q: want http or https", rawURL)
+	}
+	if !isLoopback(u.Hostname()) {
+		return nil, fmt.Errorf("webhook URL %q must point at this machine (localhost or a loopback address)", rawURL)
+	}
+	return &WebhookNotifier{URL: rawURL}, nil
+}
+
+// webhookPayload is the body posted for each reminder.
+type webhookPayload struct {
+	Title string     `json:"title"`
+	Lead  string     `json:"lead"`
+	Task  taskOutput `json:"task"`
+}
+
+func (n *WebhookNotifier) Notify(r Reminder) error {
+	body, err := json.Marshal(webhookPayload{Title: r.Title(), Lead: r.Lead.String(), Task: newTaskOutput(r.Task)})
+	if err != nil {
+		return err
+	}
+	client := n.Client
+	if client == nil {
+		client = webhookClient
+	}
+	resp, err := client.Post(n.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook: %s replied %s", n.URL, resp.Status)
+	}
+	return nil
+}
+
+// RemindersPath is where the reminders already sent for a data file are
+// recorded, one JSON object per line.
+func RemindersPath(dataPath string) string {
+	return dataPath + ".reminders"
+}
+
+// sentReminder is a line of the reminders file, recording a reminder sent
+// through one notifier. A task whose due date changes gets its reminders
+// again.
+type sentReminder struct {
+	Task int           `json:"task"`
+	Due  time.Time     `json:"due"`
+	Lead time.Duration `json:"lead"`
+	Via  string        `json:"via"`
+	Sent time.Time     `json:"sent"`
+}
+
+func reminderKey(id int, due time.Time, via string) string {
+	return fmt.Sprintf("%d %s %s", id, due.UTC().Format(time.RFC3339), via)
+}
+
+// maxLate is how long past its due date a task still gets reminders, so
+// a daemon started after a long break does not announce every old task.
+const maxLate = 24 * time.Hour
+
+// Daemon sends reminders for the open tasks of a store Leads before they
+// are due, through each of Notifiers, which are named as in the reminders
+// file. Reminders sent are recorded in LogPath for each notifier, so they
+// are not repeated after a restart and a notifier that fails does not
+// make the others send them again.
+type Daemon struct {
+	Store     Store
+	Notifiers map[string]Notifier
+	Leads     []time.Duration
+	LogPath   string
+	Errors    io.Writer // where Run reports failed checks, if set
+
+	sent map[string]time.Duration // shortest lead sent by task, due date and notifier
+}
+
+// Check sends the reminders that are due at now and records them,
+// returning those that went out through at least one notifier. Of the
+// leads that have passed for a task only the shortest is sent, and only if
+// no reminder as short was sent before, so a task first seen an hour
+// before it is due does not also get its one day reminder. Notifiers that
+// fail are tried again on the next check.
+func (d *Daemon) Check(now time.Time) ([]Reminder, error) {
+	if d.sent == nil {
+		if err := d.loadSent(); err != nil {
+			return nil, err
+		}
+	}
+	tasks, err := d.Store.List()
+	if err != nil {
+		return nil, err
+	}
+	leads := slices.Clone(d.Leads)
+	slices.Sort(leads)
+	names := slices.Sorted(maps.Keys(d.Notifiers))
+
+	var (
+		sent []Reminder
+		errs []error
+	)
+	for _, task := range tasks {
+		if task.Completed() || task.Due.IsZero() || now.Sub(task.Due) > maxLate {
+			continue
+		}
+		i := slices.IndexFunc(leads, func(lead time.Duration) bool { return !now.Before(task.Due.Add(-lead)) })
+		if i < 0 {
+			continue
+		}
+		reminder := Reminder{Task: task, Lead: leads[i]}
+		delivered := false
+		for _, name := range names {
+			// Longer leads have passed too and are not sent any more
+			if lead, ok := d.sent[reminderKey(task.ID, task.Due, name)]; ok && lead <= leads[i] {
+				continue
+			}
+			if err := d.Notifiers[name].Notify(reminder); err != nil {
+				errs = append(errs, fmt.Errorf("task %d: %s: %w", task.ID, name, err))
+				continue
+			}
+			record := sentReminder{Task: task.ID, Due: task.Due, Lead: leads[i], Via: name, Sent: now}
+			if err := d.recordSent(record); err != nil {
+				return sent, err
+			}
+			delivered = true
+		}
+		if delivered {
+			sent = append(sent, reminder)
+		}
+	}
+	return sent, errors.Join(errs...)
+}
+
+// Run checks for reminders every interval until ctx is done. Failed checks
+// are reported to Errors and do not stop the daemon.
+func (d *Daemon) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.Check(time.Now()); err != nil && d.Errors != nil {
+			fmt.Fprintf(d.Errors, "%s %v\n", time.Now().Format("15:04"), err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Daemon) loadSent() error {
+	d.sent = make(map[string]time.Duration)
+	f, err := os.Open(d.LogPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record sentReminder
+		// A line cut short by a crash only means a reminder may repeat
+		if json.Unmarshal(scanner.Bytes(), &record) == nil {
+			d.remember(record)
+		}
+	}
+	return scanner.Err()
+}
+
+func (d *Daemon) recordSent(record sentReminder) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(d.LogPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	d.remember(record)
+	return nil
+}
+
+func (d *Daemon) remember(record sentReminder) {
+	key := reminderKey(record.Task, record.Due, record.Via)
+	if lead, ok := d.sent[key]; !ok || record.Lead < lead {
+		d.sent[key] = record.Lead
+	}
+}
@@ -0,0 +1,143 @@
+package tasks_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+)
+
+// recorder keeps the reminders it is given, failing while fail is set.
+type recorder struct {
+	got  []tasks.Reminder
+	fail bool
+}
+
+func (r *recorder) Notify(reminder tasks.Reminder) error {
+	if r.fail {
+		return errors.New("notifier down")
+	}
+	r.got = append(r.got, reminder)
+	return nil
+}
+
+func TestDaemon(t *testing.T) {
+	now := time.Date(2026, 10, 12, 9, 0, 0, 0, time.Local)
+	s := tasks.NewMemoryStore(
+		tasks.Tasks{ID: 1, Description: "Call dentist", CreatedAt: now, Due: now.Add(5 * time.Minute)},
+		tasks.Tasks{ID: 2, Description: "Send invoice", CreatedAt: now, Due: now.Add(3 * time.Hour)},
+		tasks.Tasks{ID: 3, Description: "Old chore", CreatedAt: now, Due: now.Add(-48 * time.Hour)},
+		tasks.Tasks{ID: 4, Description: "Done already", CreatedAt: now, Due: now.Add(time.Minute), CompletedAt: now},
+		tasks.Tasks{ID: 5, Description: "No due date", CreatedAt: now},
+	)
+	log := filepath.Join(t.TempDir(), "tasks.csv.reminders")
+	notifier := &recorder{}
+	newDaemon := func() *tasks.Daemon {
+		return &tasks.Daemon{
+			Store:     s,
+			Notifiers: map[string]tasks.Notifier{"test": notifier},
+			Leads:     []time.Duration{time.Hour, 10 * time.Minute},
+			LogPath:   log,
+		}
+	}
+	d := newDaemon()
+
+	// Only the shortest lead that has passed is sent
+	sent, err := d.Check(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || sent[0].Task.ID != 1 || sent[0].Lead != 10*time.Minute {
+		t.Fatalf("first check sent %+v, want task 1 at 10m", sent)
+	}
+	if sent, _ := d.Check(now.Add(time.Minute)); len(sent) != 0 {
+		t.Errorf("second check sent %+v again", sent)
+	}
+
+	// A restarted daemon remembers what it sent
+	d = newDaemon()
+	sent, err = d.Check(now.Add(2 * time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || sent[0].Task.ID != 2 || sent[0].Lead != time.Hour {
+		t.Fatalf("after restart sent %+v, want task 2 at 1h", sent)
+	}
+
+	// Moving the due date brings its reminders back
+	task, _ := s.Get(1)
+	task.Due = now.Add(6 * time.Hour)
+	s.Update(task)
+	if sent, _ := d.Check(now.Add(5*time.Hour + 55*time.Minute)); len(sent) != 2 {
+		t.Errorf("after moving the due date sent %+v, want tasks 1 and 2", sent)
+	}
+
+	// A failing notifier is tried again without the others repeating
+	mustCreateDue(t, s, 6, now.Add(7*time.Hour))
+	down := &recorder{fail: true}
+	d.Notifiers["down"] = down
+	if sent, err := d.Check(now.Add(6*time.Hour + 55*time.Minute)); err == nil || len(sent) != 1 {
+		t.Errorf("with one notifier down sent %+v, %v", sent, err)
+	}
+	down.fail = false
+	if sent, _ := d.Check(now.Add(6*time.Hour + 56*time.Minute)); !slices.ContainsFunc(sent, func(r tasks.Reminder) bool { return r.Task.ID == 6 }) {
+		t.Errorf("retry sent %+v, want task 6", sent)
+	}
+	if got := slices.DeleteFunc(notifier.got, func(r tasks.Reminder) bool { return r.Task.ID != 6 }); len(got) != 1 {
+		t.Errorf("working notifier sent task 6 %d times, want once", len(got))
+	}
+}
+
+func mustCreateDue(t *testing.T, s tasks.Store, id int, due time.Time) {
+	t.Helper()
+	if _, err := s.Create(tasks.Tasks{ID: id, Description: "Task", CreatedAt: time.Now(), Due: due}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	for _, url := range []string{"http://example.com/hook", "ftp://localhost/hook", "http://10.0.0.1/"} {
+		if _, err := tasks.NewWebhookNotifier(url); err == nil {
+			t.Errorf("webhook %s accepted", url)
+		}
+	}
+
+	var payload struct {
+		Title string `json:"title"`
+		Lead  string `json:"lead"`
+		Task  struct {
+			ID int `json:"id"`
+		} `json:"task"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	n, err := tasks.NewWebhookNotifier(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reminder := tasks.Reminder{Task: tasks.Tasks{ID: 7, Description: "Pay rent", Due: time.Now().Add(time.Hour)}, Lead: time.Hour}
+	if err := n.Notify(reminder); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Task.ID != 7 || payload.Lead != "1h0m0s" || payload.Title == "" {
+		t.Errorf("webhook got %+v", payload)
+	}
+
+	// Redirects may not take reminders off the machine
+	redirect := httptest.NewServer(http.RedirectHandler("http://example.com/hook", http.StatusTemporaryRedirect))
+	defer redirect.Close()
+	if n, _ = tasks.NewWebhookNotifier(redirect.URL); n.Notify(reminder) == nil {
+		t.Error("webhook followed a redirect off the machine")
+	}
+}
@@ -0,0 +1,241 @@
+package tasks
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Search modes understood by SearchTasks
+const (
+	SearchSubstring = "substring"
+	SearchFuzzy     = "fuzzy"
+	SearchRegex     = "regex"
+)
+
+// SearchResult is a task whose description matched a search.
+type SearchResult struct {
+	Task     Tasks
+	Archived bool
+	Score    float64  // relevance weighed with recency, higher first
+	Matches  [][2]int // byte ranges of the description that matched
+}
+
+// SearchTasks looks for query in the descriptions of the tasks in s and,
+// unless it is nil, archive. Results are ranked by how well they match and
+// how recently the task was created or completed, best first; limit caps
+// their number when positive.
+//
+// Substring mode matches the query as typed, fuzzy mode its letters in
+// order with anything in between, and regex mode a regular expression.
+// Queries without capital letters ignore case.
+func SearchTasks(s, archive Store, query, mode string, limit int) ([]SearchResult, error) {
+	match, err := newMatcher(query, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var results []SearchResult
+	search := func(from Store, archived bool) error {
+		tasks, err := from.List()
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			relevance, matches := match(task.Description)
+			if matches == nil {
+				continue
+			}
+			results = append(results, SearchResult{
+				Task:     task,
+				Archived: archived,
+				Score:    relevance + 0.5*recency(task, now),
+				Matches:  matches,
+			})
+		}
+		return nil
+	}
+	if err := search(s, false); err != nil {
+		return nil, err
+	}
+	if archive != nil {
+		if err := search(archive, true); err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortStableFunc(results, func(a, b SearchResult) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Task.ID, a.Task.ID)
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// recency falls from 1 for a task touched now to 0.5 a month later.
+func recency(t Tasks, now time.Time) float64 {
+	age := now.Sub(latest(t.CreatedAt, t.CompletedAt))
+	return 1 / (1 + max(age.Hours(), 0)/(24*30))
+}
+
+// matcher scores a description against the query, from 1 for a bare match
+// to about 3 for an exact one. It returns nil matches when there is none.
+type matcher func(text string) (float64, [][2]int)
+
+// regexEscape matches the escapes of a regular expression, such as \S or
+// \p{Greek}, whose letters say nothing about case.
+var regexEscape = regexp.MustCompile(`\\([pP](\{[^}]*\}|.)|.)`)
+
+func newMatcher(query, mode string) (matcher, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("empty search")
+	}
+	// Smart case: a capital letter makes the search case sensitive
+	cased := query
+	if mode == SearchRegex {
+		cased = regexEscape.ReplaceAllString(query, "")
+	}
+	ignoreCase := !strings.ContainsFunc(cased, unicode.IsUpper)
+
+	switch mode {
+	case "", SearchSubstring:
+		query = regexp.QuoteMeta(query)
+	case SearchRegex:
+	case SearchFuzzy:
+		return fuzzyMatcher(query, ignoreCase), nil
+	default:
+		return nil, fmt.Errorf("unknown search mode %q (want %s, %s or %s)", mode, SearchSubstring, SearchFuzzy, SearchRegex)
+	}
+
+	re, err := regexp.Compile(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression: %w", err)
+	}
+	if ignoreCase {
+		re = regexp.MustCompile("(?i)" + query)
+	}
+	return func(text string) (float64, [][2]int) {
+		var matches [][2]int
+		covered := 0
+		for _, m := range re.FindAllStringIndex(text, -1) {
+			if m[0] < m[1] {
+				matches = append(matches, [2]int{m[0], m[1]})
+				covered += m[1] - m[0]
+			}
+		}
+		if matches == nil {
+			return 0, nil
+		}
+		first := matches[0]
+		score := 1 + float64(covered)/float64(len(text))
+		if wordStart(text, first[0]) {
+			score += 0.5
+			if wordEnd(text, first[1]) {
+				score += 0.25 // a whole word
+			}
+		}
+		score += 0.25 * (1 - float64(first[0])/float64(len(text)))
+		score += 0.05 * float64(min(len(matches)-1, 5))
+		return score, matches
+	}, nil
+}
+
+// fuzzyMatcher matches the letters of the query in order. Matches that
+// run together, start words and span less of the text score higher.
+func fuzzyMatcher(query string, ignoreCase bool) matcher {
+	pattern := []rune(strings.Join(strings.Fields(query), ""))
+	fold := func(r rune) rune {
+		if ignoreCase {
+			return unicode.ToLower(r)
+		}
+		return r
+	}
+
+	return func(text string) (float64, [][2]int) {
+		// Find the first full match, then walk back from its end to the
+		// latest start, giving the shortest window ending there
+		var positions []int
+		i := 0
+		for pos, r := range text {
+			if i < len(pattern) && fold(r) == fold(pattern[i]) {
+				positions = append(positions, pos)
+				i++
+			}
+		}
+		if i < len(pattern) {
+			return 0, nil
+		}
+		_, size := utf8.DecodeRuneInString(text[positions[len(positions)-1]:])
+		pos := positions[len(positions)-1] + size
+		for j := len(pattern) - 1; j >= 0; {
+			r, size := utf8.DecodeLastRuneInString(text[:pos])
+			pos -= size
+			if fold(r) == fold(pattern[j]) {
+				positions[j] = pos
+				j--
+			}
+		}
+
+		var (
+			matches     [][2]int
+			consecutive int
+			starts      int
+		)
+		for _, pos := range positions {
+			_, size := utf8.DecodeRuneInString(text[pos:])
+			if wordStart(text, pos) {
+				starts++
+			}
+			if n := len(matches); n > 0 && matches[n-1][1] == pos {
+				matches[n-1][1] = pos + size
+				consecutive++
+				continue
+			}
+			matches = append(matches, [2]int{pos, pos + size})
+		}
+
+		n := float64(len(pattern))
+		window := float64(utf8.RuneCountInString(text[positions[0]:matches[len(matches)-1][1]]))
+		score := 1 + n/window
+		score += 0.5 * float64(consecutive) / max(n-1, 1)
+		score += 0.5 * float64(starts) / n
+		return score, matches
+	}
+}
+
+// wordStart reports whether a word starts at byte i of text.
+func wordStart(text string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	if i >= len(text) {
+		return false
+	}
+	before, _ := utf8.DecodeLastRuneInString(text[:i])
+	at, _ := utf8.DecodeRuneInString(text[i:])
+	return !isWord(before) && isWord(at)
+}
+
+// wordEnd reports whether a word ends just before byte i of text.
+func wordEnd(text string, i int) bool {
+	if i == 0 {
+		return false
+	}
+	before, _ := utf8.DecodeLastRuneInString(text[:i])
+	at, _ := utf8.DecodeRuneInString(text[i:])
+	return isWord(before) && (i == len(text) || !isWord(at))
+}
+
+func isWord(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
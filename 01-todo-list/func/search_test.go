@@ -0,0 +1,101 @@
+package tasks_test
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+)
+
+func searchIDs(results []tasks.SearchResult) []int {
+	var ids []int
+	for _, result := range results {
+		ids = append(ids, result.Task.ID)
+	}
+	return ids
+}
+
+func TestSearchTasks(t *testing.T) {
+	now := time.Now()
+	s := tasks.NewMemoryStore(
+		tasks.Tasks{ID: 1, Description: "Write quarterly report", CreatedAt: now},
+		tasks.Tasks{ID: 2, Description: "Report bug in parser", CreatedAt: now},
+		tasks.Tasks{ID: 3, Description: "Call the plumber about the reporting", CreatedAt: now},
+		tasks.Tasks{ID: 4, Description: "Buy milk", CreatedAt: now},
+	)
+	archive := tasks.NewMemoryStore(
+		tasks.Tasks{ID: 5, Description: "Old report", CreatedAt: now.AddDate(-1, 0, 0), CompletedAt: now.AddDate(-1, 0, 0)},
+		tasks.Tasks{ID: 6, Description: "Write quarterly report", CreatedAt: now.AddDate(-1, 0, 0), CompletedAt: now.AddDate(-1, 0, 0)},
+	)
+
+	tests := []struct {
+		query, mode string
+		want        []int
+	}{
+		// Whole words early in the description first, old tasks lower
+		{"report", tasks.SearchSubstring, []int{2, 1, 5, 3, 6}},
+		// The same match ranks recent tasks first
+		{"quarterly", tasks.SearchSubstring, []int{1, 6}},
+		{"Report", tasks.SearchSubstring, []int{2}},
+		{"qrtrpt", tasks.SearchFuzzy, []int{1, 6}},
+		{"bg prs", tasks.SearchFuzzy, []int{2}},
+		{`^(call|buy) `, tasks.SearchRegex, []int{4, 3}},
+		// Escapes such as \S keep the search case insensitive
+		{`^re\S+ bug`, tasks.SearchRegex, []int{2}},
+		{`^Re\S+ bug`, tasks.SearchRegex, []int{2}},
+		{`^RE\S+ bug`, tasks.SearchRegex, nil},
+		{`\p{Lu}ug`, tasks.SearchRegex, []int{2}},
+		{"milk$", tasks.SearchSubstring, nil},
+	}
+	for _, tt := range tests {
+		results, err := tasks.SearchTasks(s, archive, tt.query, tt.mode, 0)
+		if err != nil {
+			t.Fatalf("%s %q: %v", tt.mode, tt.query, err)
+		}
+		if got := searchIDs(results); !slices.Equal(got, tt.want) {
+			t.Errorf("%s %q: got %v, want %v", tt.mode, tt.query, got, tt.want)
+		}
+	}
+
+	results, _ := tasks.SearchTasks(s, archive, "report", tasks.SearchSubstring, 2)
+	if len(results) != 2 {
+		t.Errorf("limit 2 gave %d results", len(results))
+	}
+	results, _ = tasks.SearchTasks(s, nil, "old", tasks.SearchSubstring, 0)
+	if len(results) != 0 {
+		t.Errorf("searched the archive without one: %v", searchIDs(results))
+	}
+	if _, err := tasks.SearchTasks(s, nil, "(", tasks.SearchRegex, 0); err == nil {
+		t.Error("invalid regular expression accepted")
+	}
+}
+
+func TestSearchMatches(t *testing.T) {
+	s := tasks.NewMemoryStore(tasks.Tasks{ID: 1, Description: "Write quarterly report", CreatedAt: time.Now()})
+	results, err := tasks.SearchTasks(s, nil, "wqr", tasks.SearchFuzzy, 0)
+	if err != nil || len(results) != 1 {
+		t.Fatalf("got %v, %v", results, err)
+	}
+	// The shortest window: the r of quarterly rather than the r of Write
+	want := [][2]int{{0, 1}, {6, 7}, {9, 10}}
+	if got := results[0].Matches; !slices.Equal(got, want) {
+		t.Errorf("got matches %v, want %v", got, want)
+	}
+
+	var out bytes.Buffer
+	p := tasks.Printer{Out: &out, Format: tasks.OutputTable, Color: true}
+	if err := p.PrintSearchResults(results); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "\x1b[1;33mW\x1b[0mrite \x1b[1;33mq\x1b[0m") {
+		t.Errorf("matches not highlighted:\n%q", out.String())
+	}
+	plain := strings.NewReplacer("\x1b[1;33m", "", "\x1b[0m", "").Replace(out.String())
+	lines := strings.Split(plain, "\n")
+	if strings.Index(lines[0], "|Project") != strings.Index(lines[1], "|-") {
+		t.Errorf("highlighting broke the columns:\n%s", plain)
+	}
+}
@@ -0,0 +1,322 @@
+package tasks
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// migrations are applied in order; PRAGMA user_version records how many
+// of them a database has already seen.
+var migrations = []string{
+	`CREATE TABLE tasks (
+		id           INTEGER PRIMARY KEY,
+		description  TEXT    NOT NULL,
+		created_at   TEXT    NOT NULL,
+		is_completed INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX tasks_is_completed ON tasks (is_completed);`,
+	// AUTOINCREMENT keeps IDs of deleted tasks from being reused.
+	`CREATE TABLE tasks_new (
+		id           INTEGER PRIMARY KEY AUTOINCREMENT,
+		description  TEXT    NOT NULL,
+		created_at   TEXT    NOT NULL,
+		is_completed INTEGER NOT NULL DEFAULT 0
+	);
+	INSERT INTO tasks_new SELECT id, description, created_at, is_completed FROM tasks;
+	DROP TABLE tasks;
+	ALTER TABLE tasks_new RENAME TO tasks;
+	CREATE INDEX tasks_is_completed ON tasks (is_completed);`,
+	// Completed tasks remember when they were done; tasks finished before
+	// this migration are dated with the time it ran.
+	`ALTER TABLE tasks ADD COLUMN completed_at TEXT;
+	UPDATE tasks SET completed_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE is_completed;
+	DROP INDEX tasks_is_completed;
+	ALTER TABLE tasks DROP COLUMN is_completed;
+	CREATE INDEX tasks_completed_at ON tasks (completed_at);`,
+	`ALTER TABLE tasks ADD COLUMN due TEXT;
+	CREATE INDEX tasks_due ON tasks (due);`,
+	// Tags are kept space separated like in the CSV store.
+	`ALTER TABLE tasks ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE tasks ADD COLUMN tags TEXT NOT NULL DEFAULT '';
+	ALTER TABLE tasks ADD COLUMN project TEXT NOT NULL DEFAULT '';
+	CREATE INDEX tasks_priority ON tasks (priority);
+	CREATE INDEX tasks_project ON tasks (project);`,
+	`ALTER TABLE tasks ADD COLUMN recur TEXT NOT NULL DEFAULT '';
+	ALTER TABLE tasks ADD COLUMN series INTEGER NOT NULL DEFAULT 0;
+	CREATE INDEX tasks_series ON tasks (series);`,
+	// Blockers are kept space separated, like tags.
+	`ALTER TABLE tasks ADD COLUMN parent INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE tasks ADD COLUMN blocked_by TEXT NOT NULL DEFAULT '';
+	CREATE INDEX tasks_parent ON tasks (parent);`,
+	// Transitions are kept as space separated state@time pairs.
+	`ALTER TABLE tasks ADD COLUMN state TEXT NOT NULL DEFAULT '';
+	ALTER TABLE tasks ADD COLUMN transitions TEXT NOT NULL DEFAULT '';
+	CREATE INDEX tasks_state ON tasks (state);`,
+	// Time entries are kept as space separated start/end pairs.
+	`ALTER TABLE tasks ADD COLUMN time_log TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE tasks ADD COLUMN updated_at TEXT;`,
+}
+
+// SQLiteStore keeps tasks in an indexed SQLite database.
+type SQLiteStore struct {
+	conn *sql.DB
+	db   querier // conn, or the transaction inside Batch
+}
+
+// querier is what *sql.DB and *sql.Tx have in common.
+type querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	// SQLite reads the path as a URI, so characters such as ? # and %
+	// must be escaped to reach the file system unchanged
+	dsn := url.URL{
+		Scheme: "file",
+		Opaque: (&url.URL{Path: path}).EscapedPath(),
+		RawQuery: url.Values{
+			"_pragma": {fmt.Sprintf("busy_timeout(%d)", LockTimeout.Milliseconds())},
+			"_txlock": {"immediate"},
+		}.Encode(),
+	}
+	db, err := sql.Open("sqlite", dsn.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	s := &SQLiteStore{conn: db, db: db}
+	if err := s.migrate(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *SQLiteStore) migrate() error {
+	var version int
+	if err := s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for ; version < len(migrations); version++ {
+		tx, err := s.conn.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(migrations[version]); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to apply migration %d: %w", version+1, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", version+1)); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const selectTasks = `SELECT id, description, created_at, completed_at, due, priority, tags, project, recur, series, parent, blocked_by, state, transitions, time_log, updated_at FROM tasks`
+
+func (s *SQLiteStore) List() ([]Tasks, error) {
+	rows, err := s.db.Query(selectTasks + ` ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []Tasks
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+func (s *SQLiteStore) Get(id int) (Tasks, error) {
+	task, err := scanTask(s.db.QueryRow(selectTasks+` WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Tasks{}, notFound(id)
+	}
+	return task, err
+}
+
+func (s *SQLiteStore) Create(task Tasks) (Tasks, error) {
+	var id any
+	if task.ID != 0 {
+		id = task.ID
+	}
+	res, err := s.db.Exec(
+		`INSERT INTO tasks (id, description, created_at, completed_at, due, priority, tags, project, recur, series, parent, blocked_by, state, transitions, time_log, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, task.Description, formatTime(task.CreatedAt), nullTime(task.CompletedAt), nullTime(task.Due),
+		task.Priority, joinTags(task.Tags), task.Project, task.Recur, task.Series, task.Parent, joinIDs(task.BlockedBy),
+		task.State, joinTransitions(task.Transitions), joinTimeLog(task.TimeLog), nullTime(task.UpdatedAt),
+	)
+	if err != nil {
+		return Tasks{}, err
+	}
+	newID, err := res.LastInsertId()
+	if err != nil {
+		return Tasks{}, err
+	}
+	task.ID = int(newID)
+	return task, nil
+}
+
+func (s *SQLiteStore) Update(task Tasks) error {
+	res, err := s.db.Exec(
+		`UPDATE tasks SET description = ?, created_at = ?, completed_at = ?, due = ?,
+		priority = ?, tags = ?, project = ?, recur = ?, series = ?, parent = ?, blocked_by = ?,
+		state = ?, transitions = ?, time_log = ?, updated_at = ? WHERE id = ?`,
+		task.Description, formatTime(task.CreatedAt), nullTime(task.CompletedAt), nullTime(task.Due),
+		task.Priority, joinTags(task.Tags), task.Project, task.Recur, task.Series, task.Parent, joinIDs(task.BlockedBy),
+		task.State, joinTransitions(task.Transitions), joinTimeLog(task.TimeLog), nullTime(task.UpdatedAt), task.ID,
+	)
+	return checkAffected(res, err, task.ID)
+}
+
+func (s *SQLiteStore) Delete(id int) error {
+	res, err := s.db.Exec(`DELETE FROM tasks WHERE id = ?`, id)
+	return checkAffected(res, err, id)
+}
+
+// checkAffected turns a statement that touched no rows into a not found error.
+func checkAffected(res sql.Result, err error, id int) error {
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notFound(id)
+	}
+	return nil
+}
+
+// Batch runs fn inside a transaction that takes the write lock up front,
+// so what fn reads cannot change before it writes.
+func (s *SQLiteStore) Batch(fn func(tx Store) error) error {
+	if s.conn == nil {
+		return fn(s) // already inside a batch
+	}
+	tx, err := s.conn.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(&SQLiteStore{db: tx}); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row scanner) (Tasks, error) {
+	var (
+		task        Tasks
+		createdAt   string
+		completedAt sql.NullString
+		due         sql.NullString
+		tags        string
+		blockedBy   string
+		transitions string
+		timeLog     string
+		updatedAt   sql.NullString
+	)
+	err := row.Scan(
+		&task.ID, &task.Description, &createdAt, &completedAt, &due,
+		&task.Priority, &tags, &task.Project, &task.Recur, &task.Series, &task.Parent, &blockedBy,
+		&task.State, &transitions, &timeLog, &updatedAt,
+	)
+	if err != nil {
+		return Tasks{}, err
+	}
+	task.Tags = splitTags(tags)
+	if task.BlockedBy, err = splitIDs(blockedBy); err != nil {
+		return Tasks{}, corruptTask(task.ID, "blocked_by", err)
+	}
+	if task.Transitions, err = splitTransitions(transitions); err != nil {
+		return Tasks{}, corruptTask(task.ID, "transitions", err)
+	}
+	for i, t := range task.Transitions {
+		task.Transitions[i].At = t.At.Local()
+	}
+	if task.TimeLog, err = splitTimeLog(timeLog); err != nil {
+		return Tasks{}, corruptTask(task.ID, "time_log", err)
+	}
+	for i, e := range task.TimeLog {
+		task.TimeLog[i] = TimeEntry{Start: e.Start.Local(), End: e.End.Local()}
+	}
+	times := []struct {
+		column string
+		value  string
+		dst    *time.Time
+	}{
+		{"created_at", createdAt, &task.CreatedAt},
+		{"completed_at", completedAt.String, &task.CompletedAt},
+		{"due", due.String, &task.Due},
+		{"updated_at", updatedAt.String, &task.UpdatedAt},
+	}
+	for _, t := range times {
+		if *t.dst, err = parseTime(t.value); err != nil {
+			return Tasks{}, corruptTask(task.ID, t.column, err)
+		}
+	}
+	return task, nil
+}
+
+// corruptTask reports a column of a stored task that cannot be read back.
+func corruptTask(id int, column string, err error) error {
+	return fmt.Errorf("%w: task %d: %s: %w", ErrCorruptRecord, id, column, err)
+}
+
+// formatTime stores timestamps in UTC so they sort correctly as text.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// parseTime reads a stored timestamp back in local time. An empty string
+// is the zero time.
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.Local(), nil
+}
+
+// nullTime stores a zero time as NULL.
+func nullTime(t time.Time) sql.NullString {
+	if t.IsZero() {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: formatTime(t), Valid: true}
+}
@@ -0,0 +1,47 @@
+package tasks
+
+import "fmt"
+
+// Store is the persistence layer behind every task command. Get, Update and
+// Delete return an error wrapping ErrNotFound for unknown IDs.
+type Store interface {
+	List() ([]Tasks, error)
+	Get(id int) (Tasks, error)
+	// Create stores a new task and returns it. A zero ID asks the store
+	// to allocate the next one; IDs are never handed out twice.
+	Create(task Tasks) (Tasks, error)
+	Update(task Tasks) error
+	Delete(id int) error
+	// Batch runs fn as one locked transaction: every change made through
+	// tx is stored, or none is when fn returns an error.
+	Batch(fn func(tx Store) error) error
+	Close() error
+}
+
+// Supported store backends
+const (
+	StoreCSV    = "csv"
+	StoreSQLite = "sqlite"
+)
+
+// OpenStore opens the backend named by kind at the given path.
+func OpenStore(kind, path string) (Store, error) {
+	switch kind {
+	case StoreCSV:
+		return NewCSVStore(path), nil
+	case StoreSQLite:
+		return NewSQLiteStore(path)
+	default:
+		return nil, fmt.Errorf("unknown store %q (want %s or %s)", kind, StoreCSV, StoreSQLite)
+	}
+}
+
+// findTask returns the task with the given id from a listed slice.
+func findTask(tasks []Tasks, id int) (Tasks, error) {
+	for _, task := range tasks {
+		if task.ID == id {
+			return task, nil
+		}
+	}
+	return Tasks{}, notFound(id)
+}
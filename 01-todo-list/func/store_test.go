@@ -0,0 +1,182 @@
+package tasks_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+)
+
+// openStores returns a fresh store of every backend in a temp directory.
+func openStores(t *testing.T) map[string]func() tasks.Store {
+	t.Helper()
+	dir := t.TempDir()
+	return map[string]func() tasks.Store{
+		tasks.StoreCSV: func() tasks.Store {
+			return tasks.NewCSVStore(filepath.Join(dir, "db.csv"))
+		},
+		tasks.StoreSQLite: func() tasks.Store {
+			s, err := tasks.NewSQLiteStore(filepath.Join(dir, "db.sqlite"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return s
+		},
+	}
+}
+
+func mustCreate(t *testing.T, s tasks.Store, description string) tasks.Tasks {
+	t.Helper()
+	task, err := s.Create(tasks.Tasks{Description: description, CreatedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("create %q: %v", description, err)
+	}
+	return task
+}
+
+func TestIDsSurviveDeletes(t *testing.T) {
+	for name, open := range openStores(t) {
+		t.Run(name, func(t *testing.T) {
+			s := open()
+			defer s.Close()
+
+			for i, want := range []int{1, 2, 3} {
+				if got := mustCreate(t, s, "task").ID; got != want {
+					t.Fatalf("task %d: got ID %d, want %d", i, got, want)
+				}
+			}
+
+			// Deleting from the middle must not free an ID for reuse
+			if err := s.Delete(2); err != nil {
+				t.Fatal(err)
+			}
+			if got := mustCreate(t, s, "after middle delete").ID; got != 4 {
+				t.Fatalf("got ID %d, want 4", got)
+			}
+
+			// Nor may deleting the highest ID
+			if err := s.Delete(4); err != nil {
+				t.Fatal(err)
+			}
+			if got := mustCreate(t, s, "after last delete").ID; got != 5 {
+				t.Fatalf("got ID %d, want 5", got)
+			}
+
+			list, err := s.List()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []int
+			for _, task := range list {
+				ids = append(ids, task.ID)
+			}
+			if len(ids) != 3 || ids[0] != 1 || ids[1] != 3 || ids[2] != 5 {
+				t.Fatalf("got IDs %v, want [1 3 5]", ids)
+			}
+		})
+	}
+}
+
+func TestIDsPersistAcrossReopen(t *testing.T) {
+	for name, open := range openStores(t) {
+		t.Run(name, func(t *testing.T) {
+			s := open()
+			mustCreate(t, s, "one")
+			mustCreate(t, s, "two")
+			if err := s.Delete(2); err != nil {
+				t.Fatal(err)
+			}
+			s.Close()
+
+			s = open()
+			defer s.Close()
+			if got := mustCreate(t, s, "three").ID; got != 3 {
+				t.Fatalf("got ID %d, want 3", got)
+			}
+		})
+	}
+}
+
+func TestLookupByID(t *testing.T) {
+	for name, open := range openStores(t) {
+		t.Run(name, func(t *testing.T) {
+			s := open()
+			defer s.Close()
+
+			for range 3 {
+				mustCreate(t, s, "task")
+			}
+			if err := s.Delete(1); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Delete(2); err != nil {
+				t.Fatal(err)
+			}
+
+			// Only one task is left but ID 3 must still be found
+			task, err := s.Get(3)
+			if err != nil {
+				t.Fatalf("get 3: %v", err)
+			}
+			task.CompletedAt = time.Now()
+			if err := s.Update(task); err != nil {
+				t.Fatalf("update 3: %v", err)
+			}
+
+			if _, err := s.Get(2); err == nil {
+				t.Fatal("get 2: expected error for deleted task")
+			}
+			if err := s.Delete(2); err == nil {
+				t.Fatal("delete 2: expected error for deleted task")
+			}
+		})
+	}
+}
+
+func TestBatch(t *testing.T) {
+	stores := openStores(t)
+	stores["memory"] = func() tasks.Store { return tasks.NewMemoryStore() }
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			s := open()
+			defer s.Close()
+			mustCreate(t, s, "one")
+
+			// A failing batch leaves nothing behind
+			err := s.Batch(func(tx tasks.Store) error {
+				mustCreate(t, tx, "two")
+				if err := tx.Delete(1); err != nil {
+					return err
+				}
+				return tx.Delete(42)
+			})
+			if !errors.Is(err, tasks.ErrNotFound) {
+				t.Fatalf("got %v, want ErrNotFound", err)
+			}
+			list, err := s.List()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(list) != 1 || list[0].ID != 1 {
+				t.Fatalf("got %+v after failed batch", list)
+			}
+
+			err = s.Batch(func(tx tasks.Store) error {
+				mustCreate(t, tx, "three")
+				return tx.Delete(1)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			list, err = s.List()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(list) != 1 || list[0].Description != "three" {
+				t.Fatalf("got %+v after batch", list)
+			}
+		})
+	}
+}
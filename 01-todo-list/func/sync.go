@@ -0,0 +1,440 @@
+package tasks
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SyncDir is the git clone a data file is synchronized through.
+func SyncDir(dataPath string) string {
+	return dataPath + ".sync"
+}
+
+// syncedRef marks the commit the data file last matched. The clone's own
+// branch is no guide to that, as a fresh clone starts out on the remote
+// branch that the tasks here have never been merged with.
+const syncedRef = "refs/tasks/synced"
+
+// syncFile is the name of the data file in the repository. It is kept as
+// CSV whatever the store, so lists can be shared between backends and
+// several lists can share a repository.
+func syncFile(dataPath string) string {
+	base := filepath.Base(dataPath)
+	return strings.TrimSuffix(base, filepath.Ext(base)) + ".csv"
+}
+
+// SetupSync points a data file at a git repository, cloning it the first
+// time and changing the remote of the clone after that.
+func SetupSync(dataPath, remote string) error {
+	dir := SyncDir(dataPath)
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		_, err := git(dir, "remote", "set-url", "origin", remote)
+		return err
+	}
+	cmd := exec.Command("git", "clone", "-q", remote, dir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git clone: %w: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// git runs a git command in dir and returns its output.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// readRevision reads the tasks of the file name as of a git revision; a
+// revision without the file holds no tasks.
+func readRevision(dir, rev, name string) ([]Tasks, error) {
+	if _, err := git(dir, "cat-file", "-e", rev+":"+name); err != nil {
+		return nil, nil
+	}
+	data, err := git(dir, "show", rev+":"+name)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := parseCSV(strings.NewReader(data), time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%s in %s: %w", name, rev, err)
+	}
+	return tasks, nil
+}
+
+// Conflict is a task changed one way here and another way in the
+// repository since they were last synchronized.
+type Conflict struct {
+	ID     int
+	Local  *Tasks // nil when deleted here
+	Remote *Tasks // nil when deleted in the repository
+}
+
+// ConflictField is a field the two sides of a conflict disagree on, as
+// written in the CSV file.
+type ConflictField struct {
+	Name, Local, Remote string
+}
+
+// Fields lists the fields that differ between the two sides, none when
+// one side deleted the task.
+func (c Conflict) Fields() []ConflictField {
+	if c.Local == nil || c.Remote == nil {
+		return nil
+	}
+	local, remote := syncRecord(*c.Local), syncRecord(*c.Remote)
+	var fields []ConflictField
+	for i, name := range csvHeader {
+		if local[i] != remote[i] {
+			fields = append(fields, ConflictField{Name: name, Local: local[i], Remote: remote[i]})
+		}
+	}
+	return fields
+}
+
+// Resolver settles a conflict by returning the version to keep: c.Local or
+// c.Remote, nil to delete the task. An error stops the merge.
+type Resolver func(c Conflict) (*Tasks, error)
+
+// KeepLocal resolves every conflict in favour of the tasks here.
+func KeepLocal(c Conflict) (*Tasks, error) {
+	return c.Local, nil
+}
+
+// KeepRemote resolves every conflict in favour of the repository.
+func KeepRemote(c Conflict) (*Tasks, error) {
+	return c.Remote, nil
+}
+
+// KeepNewer keeps the version changed last. A task deleted on one side
+// and changed on the other is kept.
+func KeepNewer(c Conflict) (*Tasks, error) {
+	switch {
+	case c.Local == nil:
+		return c.Remote, nil
+	case c.Remote == nil:
+		return c.Local, nil
+	case c.Remote.UpdatedAt.After(c.Local.UpdatedAt):
+		return c.Remote, nil
+	}
+	return c.Local, nil
+}
+
+// MergeResult is the outcome of merging the tasks of both sides.
+type MergeResult struct {
+	Tasks      []Tasks     // merged tasks in ID order
+	Pulled     int         // tasks added, changed or deleted in the repository
+	Pushed     int         // tasks added, changed or deleted here
+	Conflicts  []Conflict  // tasks changed on both sides
+	Renumbered map[int]int // tasks added here under an ID the repository also used, old ID to new
+}
+
+// MergeTasks does a three-way merge of local and remote, the tasks on
+// both sides, against base, the tasks they last had in common. Tasks are
+// matched by ID and each is taken from the side that changed it; tasks
+// changed on both sides are settled by resolve. Tasks added on both sides
+// under the same ID are both kept, the local one moving to a new ID, with
+// the local tasks referring to it following it there. New IDs are past
+// every ID on either side and past counter, the next ID the local store
+// would hand out, which is only asked for when a task has to move.
+func MergeTasks(base, local, remote []Tasks, resolve Resolver, counter func() (int, error)) (MergeResult, error) {
+	byID := func(list []Tasks) map[int]Tasks {
+		m := make(map[int]Tasks, len(list))
+		for _, task := range list {
+			m[task.ID] = task
+		}
+		return m
+	}
+	inBase, inLocal, inRemote := byID(base), byID(local), byID(remote)
+
+	var ids []int
+	for _, m := range []map[int]Tasks{inBase, inLocal, inRemote} {
+		for id := range m {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	nextID := 0
+	newID := func() (int, error) {
+		if nextID == 0 {
+			next, err := counter()
+			if err != nil {
+				return 0, err
+			}
+			nextID = max(next, ids[len(ids)-1]+1)
+		}
+		nextID++
+		return nextID - 1, nil
+	}
+
+	result := MergeResult{Renumbered: make(map[int]int)}
+	merged := make(map[int]Tasks)
+	fromLocal := make(map[int]bool) // merged tasks whose version is the local one
+	for _, id := range ids {
+		b, hasBase := inBase[id]
+		l, hasLocal := inLocal[id]
+		r, hasRemote := inRemote[id]
+		localChanged := hasBase != hasLocal || hasLocal && !sameTask(b, l)
+		remoteChanged := hasBase != hasRemote || hasRemote && !sameTask(b, r)
+
+		var keep *Tasks
+		switch {
+		case !localChanged:
+			if hasRemote {
+				keep = &r
+			}
+			if remoteChanged {
+				result.Pulled++
+			}
+		case !remoteChanged:
+			if hasLocal {
+				keep = &l
+			}
+			result.Pushed++
+		case hasLocal == hasRemote && (!hasLocal || sameTask(l, r)):
+			// The same change was made on both sides
+			if hasLocal {
+				keep = &l
+			}
+		case !hasBase:
+			moved := l
+			var err error
+			if moved.ID, err = newID(); err != nil {
+				return MergeResult{}, err
+			}
+			result.Renumbered[id] = moved.ID
+			merged[moved.ID] = moved
+			fromLocal[moved.ID] = true
+			keep = &r
+			result.Pulled++
+			result.Pushed++
+		default:
+			c := Conflict{ID: id}
+			if hasLocal {
+				c.Local = &l
+			}
+			if hasRemote {
+				c.Remote = &r
+			}
+			var err error
+			if keep, err = resolve(c); err != nil {
+				return MergeResult{}, err
+			}
+			if keep != nil && keep != c.Local && keep != c.Remote {
+				return MergeResult{}, fmt.Errorf("task %d: conflict resolved with neither version", id)
+			}
+			result.Conflicts = append(result.Conflicts, c)
+		}
+		if keep != nil {
+			if _, taken := merged[id]; taken {
+				return MergeResult{}, fmt.Errorf("task %d: merged twice", id)
+			}
+			merged[id] = *keep
+			fromLocal[id] = keep == &l
+		}
+	}
+
+	renumber := func(id int) int {
+		if moved, ok := result.Renumbered[id]; ok {
+			return moved
+		}
+		return id
+	}
+	for _, task := range merged {
+		if fromLocal[task.ID] && len(result.Renumbered) > 0 {
+			task = cloneTask(task)
+			task.Parent, task.Series = renumber(task.Parent), renumber(task.Series)
+			for i, id := range task.BlockedBy {
+				task.BlockedBy[i] = renumber(id)
+			}
+		}
+		result.Tasks = append(result.Tasks, task)
+	}
+	slices.SortFunc(result.Tasks, func(a, b Tasks) int { return cmp.Compare(a.ID, b.ID) })
+	return result, nil
+}
+
+// sameTask compares tasks by what they hold, leaving out when they were
+// last changed and the time zone their dates were written in.
+func sameTask(a, b Tasks) bool {
+	return slices.Equal(syncRecord(a), syncRecord(b))
+}
+
+func syncRecord(t Tasks) []string {
+	t = cloneTask(t)
+	t.UpdatedAt = time.Time{}
+	t.CreatedAt, t.CompletedAt, t.Due = t.CreatedAt.UTC(), t.CompletedAt.UTC(), t.Due.UTC()
+	for i, transition := range t.Transitions {
+		t.Transitions[i].At = transition.At.UTC()
+	}
+	for i, e := range t.TimeLog {
+		t.TimeLog[i] = TimeEntry{Start: e.Start.UTC(), End: e.End.UTC()}
+	}
+	return taskRecord(t)
+}
+
+// SyncResult reports what Sync did.
+type SyncResult struct {
+	MergeResult
+	Remote    string
+	Committed bool // false when the repository already held the merged tasks
+}
+
+// Sync synchronizes the tasks of s, the store of dataPath, with the git
+// repository set up by SetupSync. It fetches the repository, merges its
+// tasks with the ones here using MergeTasks, stores the result in s and
+// pushes it as a new commit. The merge base is the last commit the tasks
+// here were merged with that the repository has too, so a sync whose push
+// failed is picked up by the next one. The store stays locked while
+// conflicts are resolved.
+func Sync(s Store, dataPath string, resolve Resolver) (SyncResult, error) {
+	dir := SyncDir(dataPath)
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		return SyncResult{}, ErrNoRemote
+	}
+	var (
+		result SyncResult
+		err    error
+	)
+	if result.Remote, err = git(dir, "remote", "get-url", "origin"); err != nil {
+		return SyncResult{}, err
+	}
+	branch, err := git(dir, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return SyncResult{}, err
+	}
+	if _, err := git(dir, "fetch", "-q", "origin"); err != nil {
+		return SyncResult{}, err
+	}
+
+	// A repository nobody pushed to yet has no branch to merge with
+	name := syncFile(dataPath)
+	upstream := "origin/" + branch
+	_, err = git(dir, "rev-parse", "-q", "--verify", upstream)
+	hasUpstream := err == nil
+	var base, remote []Tasks
+	if hasUpstream {
+		if remote, err = readRevision(dir, upstream, name); err != nil {
+			return SyncResult{}, err
+		}
+		// Without a common commit every task counts as added on both sides
+		if common, err := git(dir, "merge-base", syncedRef, upstream); err == nil {
+			if base, err = readRevision(dir, common, name); err != nil {
+				return SyncResult{}, err
+			}
+		}
+	}
+
+	err = s.Batch(func(tx Store) error {
+		local, err := tx.List()
+		if err != nil {
+			return err
+		}
+		if !hasUpstream {
+			result.MergeResult = MergeResult{Tasks: local, Pushed: len(local)}
+			return nil
+		}
+		// Only the store knows the IDs deleted here, so ask it for one
+		// and give it back; IDs are never handed out twice
+		counter := func() (int, error) {
+			probe, err := tx.Create(Tasks{Description: "sync", CreatedAt: time.Now()})
+			if err != nil {
+				return 0, err
+			}
+			return probe.ID, tx.Delete(probe.ID)
+		}
+		if result.MergeResult, err = MergeTasks(base, local, remote, resolve, counter); err != nil {
+			return err
+		}
+		return applyMerge(tx, result.Tasks)
+	})
+	if err != nil {
+		return SyncResult{}, err
+	}
+
+	// Commit on top of the remote branch so that pushing is a fast-forward
+	if hasUpstream {
+		if _, err := git(dir, "reset", "-q", "--hard", upstream); err != nil {
+			return result, err
+		}
+	}
+	err = writeFileAtomic(filepath.Join(dir, name), func(w io.Writer) error {
+		return writeCSV(w, result.Tasks)
+	})
+	if err != nil {
+		return result, err
+	}
+	if _, err := git(dir, "add", "--", name); err != nil {
+		return result, err
+	}
+	if _, err := git(dir, "diff", "--cached", "--quiet"); err != nil {
+		host, _ := os.Hostname()
+		if _, err := git(dir, "commit", "-q", "-m", fmt.Sprintf("Sync %s from %s", name, cmp.Or(host, "unknown host"))); err != nil {
+			return result, err
+		}
+		result.Committed = true
+	}
+	if _, err := git(dir, "rev-parse", "-q", "--verify", "HEAD"); err != nil {
+		return result, nil // nothing was ever committed
+	}
+	if _, err := git(dir, "update-ref", syncedRef, "HEAD"); err != nil {
+		return result, err
+	}
+	if _, err := git(dir, "push", "-q", "origin", "HEAD:refs/heads/"+branch); err != nil {
+		return result, fmt.Errorf("%w (the tasks here are merged already, sync again to push them)", err)
+	}
+	return result, nil
+}
+
+// applyMerge changes the tasks of s to merged, keeping the UpdatedAt of
+// each task as merged. Two merged tasks with the same ID are an error
+// rather than one replacing the other.
+func applyMerge(s Store, merged []Tasks) error {
+	local, err := s.List()
+	if err != nil {
+		return err
+	}
+	keep := make(map[int]bool)
+	for _, task := range merged {
+		if keep[task.ID] {
+			return fmt.Errorf("task %d: merged twice", task.ID)
+		}
+		keep[task.ID] = true
+		old, err := findTask(local, task.ID)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			_, err = s.Create(task)
+		case !sameTask(old, task) || !old.UpdatedAt.Equal(task.UpdatedAt):
+			err = s.Update(task)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, task := range local {
+		if !keep[task.ID] {
+			if err := s.Delete(task.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
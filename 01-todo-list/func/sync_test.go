@@ -0,0 +1,188 @@
+package tasks_test
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+)
+
+func TestMergeTasks(t *testing.T) {
+	created := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	task := func(id int, description string, changed int) tasks.Tasks {
+		return tasks.Tasks{ID: id, Description: description, CreatedAt: created, UpdatedAt: created.Add(time.Duration(changed) * time.Hour)}
+	}
+	base := []tasks.Tasks{
+		task(1, "Unchanged", 0),
+		task(2, "Changed here", 0),
+		task(3, "Changed there", 0),
+		task(4, "Changed on both", 0),
+		task(5, "Deleted here, changed there", 0),
+		task(6, "Deleted there", 0),
+	}
+	local := []tasks.Tasks{
+		task(1, "Unchanged", 0),
+		task(2, "Changed here!", 1),
+		task(3, "Changed there", 0),
+		task(4, "Changed on both, here", 2),
+		task(6, "Deleted there", 0),
+		task(7, "Added here", 1),
+		{ID: 8, Description: "Child of 7", CreatedAt: created, Parent: 7, UpdatedAt: created},
+	}
+	remote := []tasks.Tasks{
+		task(1, "Unchanged", 0),
+		task(2, "Changed here", 0),
+		task(3, "Changed there!", 1),
+		task(4, "Changed on both, there", 1),
+		task(5, "Deleted here, changed there!", 1),
+		task(7, "Added there", 1),
+	}
+
+	// IDs up to 20 were handed out here before
+	counter := func() (int, error) { return 21, nil }
+	result, err := tasks.MergeTasks(base, local, remote, tasks.KeepNewer, counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[int]tasks.Tasks)
+	for _, task := range result.Tasks {
+		got[task.ID] = task
+	}
+	want := map[int]string{
+		1:  "Unchanged",
+		2:  "Changed here!",
+		3:  "Changed there!",
+		4:  "Changed on both, here",
+		5:  "Deleted here, changed there!",
+		7:  "Added there",
+		8:  "Child of 7",
+		21: "Added here",
+	}
+	if len(got) != len(want) {
+		t.Errorf("merged %d tasks, want %d: %+v", len(got), len(want), result.Tasks)
+	}
+	for id, description := range want {
+		if got[id].Description != description {
+			t.Errorf("task %d: got %q, want %q", id, got[id].Description, description)
+		}
+	}
+	if got[8].Parent != 21 {
+		t.Errorf("child of a renumbered task has parent %d, want 21", got[8].Parent)
+	}
+	if len(result.Renumbered) != 1 || result.Renumbered[7] != 21 {
+		t.Errorf("renumbered %v", result.Renumbered)
+	}
+	var conflicts []int
+	for _, c := range result.Conflicts {
+		conflicts = append(conflicts, c.ID)
+	}
+	if !slices.Equal(conflicts, []int{4, 5}) {
+		t.Errorf("conflicts on %v, want [4 5]", conflicts)
+	}
+	if fields := result.Conflicts[0].Fields(); len(fields) != 1 || fields[0].Name != "Description" {
+		t.Errorf("conflict fields %+v, want the description only", fields)
+	}
+
+	// The resolver decides conflicts
+	result, _ = tasks.MergeTasks(base, local, remote, tasks.KeepLocal, counter)
+	if i := slices.IndexFunc(result.Tasks, func(t tasks.Tasks) bool { return t.ID == 5 }); i >= 0 {
+		t.Errorf("task deleted here kept by KeepLocal")
+	}
+	result, _ = tasks.MergeTasks(base, local, remote, tasks.KeepRemote, counter)
+	if result.Tasks[3].Description != "Changed on both, there" {
+		t.Errorf("KeepRemote kept %q", result.Tasks[3].Description)
+	}
+
+	// The repository added more tasks than there are here; the task added
+	// here moves past all of them
+	base = []tasks.Tasks{task(1, "Shared", 0)}
+	local = []tasks.Tasks{task(1, "Shared", 0), task(2, "Added here", 1)}
+	remote = []tasks.Tasks{task(1, "Shared", 0), task(2, "Added there", 1), task(3, "Also added there", 1)}
+	result, err = tasks.MergeTasks(base, local, remote, tasks.KeepNewer, func() (int, error) { return 3, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	var descriptions []string
+	for _, task := range result.Tasks {
+		descriptions = append(descriptions, fmt.Sprintf("%d %s", task.ID, task.Description))
+	}
+	if want := []string{"1 Shared", "2 Added there", "3 Also added there", "4 Added here"}; !slices.Equal(descriptions, want) {
+		t.Errorf("merged %q, want %q", descriptions, want)
+	}
+}
+
+func TestSync(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	for _, name := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(name, "tasks")
+	}
+	for _, name := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(name, "tasks@example.com")
+	}
+	dir := t.TempDir()
+	remote := filepath.Join(dir, "remote.git")
+	if out, err := exec.Command("git", "init", "-q", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+
+	// Two machines with the same list, changes go through the journal as
+	// commands make them
+	open := func(machine string) (tasks.Store, string) {
+		path := filepath.Join(dir, machine, "tasks.csv")
+		if err := tasks.SetupSync(path, remote); err != nil {
+			t.Fatal(err)
+		}
+		return tasks.NewJournaledStore(tasks.NewCSVStore(path), tasks.JournalPath(path), "test"), path
+	}
+	laptop, laptopPath := open("laptop")
+	server, serverPath := open("server")
+	sync := func(s tasks.Store, path string) tasks.SyncResult {
+		t.Helper()
+		result, err := tasks.Sync(s.(*tasks.JournaledStore).Unwrap(), path, tasks.KeepNewer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	milk := mustCreate(t, laptop, "Buy milk")
+	if milk.UpdatedAt.IsZero() {
+		t.Error("created task has no UpdatedAt")
+	}
+	if result := sync(laptop, laptopPath); result.Pushed != 1 || !result.Committed {
+		t.Errorf("first sync: %+v", result.MergeResult)
+	}
+	mustCreate(t, server, "Deploy")
+	if result := sync(server, serverPath); result.Pulled != 1 || result.Renumbered[1] != 2 {
+		t.Errorf("server sync: %+v", result.MergeResult)
+	}
+
+	milk.Description = "Buy oat milk"
+	if err := laptop.Update(milk); err != nil {
+		t.Fatal(err)
+	}
+	sync(laptop, laptopPath)
+	sync(server, serverPath)
+	list, err := server.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Description != "Buy oat milk" || list[1].Description != "Deploy" {
+		t.Errorf("server has %+v", list)
+	}
+
+	// Nothing new on either side
+	if result := sync(server, serverPath); result.Pulled != 0 || result.Pushed != 0 || result.Committed {
+		t.Errorf("sync without changes: %+v", result)
+	}
+	if _, err := tasks.Sync(tasks.NewMemoryStore(), filepath.Join(dir, "other.csv"), tasks.KeepNewer); !errors.Is(err, tasks.ErrNoRemote) {
+		t.Errorf("sync without a repository: got %v, want ErrNoRemote", err)
+	}
+}
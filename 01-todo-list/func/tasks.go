@@ -1,12 +1,7 @@
 package tasks
 
 import (
-	"encoding/csv"
-	"fmt"
-	"os"
-	"strconv"
-	"syscall"
-	"text/tabwriter"
+	"errors"
 	"time"
 
 	"github.com/mergestat/timediff"
@@ -16,262 +11,221 @@ type Tasks struct {
 	ID          int
 	Description string
 	CreatedAt   time.Time
-	IsCompleted bool
+	CompletedAt time.Time // zero while the task is open
+	Due         time.Time // zero when the task has no deadline
+	Priority    Priority
+	Tags        []string
+	Project     string
+	Recur       string // recurrence rule, empty for one-off tasks
+	Series      int    // ID of the first task of a recurring series
+	Parent      int    // ID of the parent task, zero at the top level
+	BlockedBy   []int  // IDs of the tasks that must be done first
+	State       string // workflow state between todo and done, empty otherwise
+	Transitions []Transition
+	TimeLog     []TimeEntry // time spent, including a running timer
+	UpdatedAt   time.Time   // last change, zero for tasks unchanged since it was recorded
 }
 
-func loadFile(filepath string) (*os.File, error) {
-	f, err := os.OpenFile(filepath, os.O_RDWR|os.O_CREATE, os.ModePerm)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file for reading")
-	}
-
-	// Exclusive lock obtained on the file descriptor
-	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
-		_ = f.Close()
-		return nil, err
-	}
-
-	return f, nil
+// Completed reports whether the task has been marked as done.
+func (t Tasks) Completed() bool {
+	return !t.CompletedAt.IsZero()
 }
 
-func closeFile(f *os.File) error {
-	syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
-	return f.Close()
-}
-
-func ReadFile() ([]Tasks, error) {
-	// Open the file
-	file, err := loadFile("db/db.csv")
-	if err != nil {
-		return nil, err
-	}
-	defer closeFile(file)
-
-	// Read the file
-	data := csv.NewReader(file)
-	records, err := data.ReadAll()
-	if err != nil {
-		return nil, err
-	}
-
-	var tasks []Tasks
-	for i, record := range records {
-		if i == 0 {
-			continue // Skip header
+// status describes the completion state for display, naming the workflow
+// state of started tasks.
+func (t Tasks) status() string {
+	if !t.Completed() {
+		if t.State != "" {
+			return t.State
 		}
-		id, _ := strconv.Atoi(record[0])
-		createdAt, _ := time.Parse(time.RFC3339, record[2])
-		isCompleted, _ := strconv.ParseBool(record[3])
-		tasks = append(tasks, Tasks{
-			ID:          id,
-			Description: record[1],
-			CreatedAt:   createdAt,
-			IsCompleted: isCompleted,
-		})
-	}
-	return tasks, nil
-}
-
-func AppendToFile(task Tasks) error {
-	// Open the file in append mode
-	file, err := os.OpenFile("db/db.csv", os.O_APPEND|os.O_WRONLY, os.ModePerm)
-	if err != nil {
-		return fmt.Errorf("failed to open file for appending: %w", err)
-	}
-	defer closeFile(file)
-
-	// Lock the file
-	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
-		return fmt.Errorf("failed to lock file: %w", err)
-	}
-	defer syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
-
-	// Write the new record
-	writer := csv.NewWriter(file)
-	record := []string{
-		strconv.Itoa(task.ID),
-		task.Description,
-		task.CreatedAt.Format(time.RFC3339),
-		strconv.FormatBool(task.IsCompleted),
+		return "open"
 	}
-	if err := writer.Write(record); err != nil {
-		return fmt.Errorf("failed to write record: %w", err)
-	}
-	writer.Flush()
-
-	return nil
+	return "completed " + timeDiff(t.CompletedAt)
 }
 
 func timeDiff(createdAt time.Time) string {
 	return timediff.TimeDiff(createdAt)
 }
 
-func ShowAllTask() {
-	// Read the file
-	tasks, err := ReadFile()
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		return
-	}
-
-	// Create a new tabwriter
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.Debug)
-	defer w.Flush() // Flush the writer
-
-	// show the header
-	fmt.Fprintln(w, "ID\tDescription\tCreated At\tCompleted")
+// TaskList is the result of ListTasks, grouped as the options asked.
+type TaskList struct {
+	Status string // status shown, StatusAll when mixed
+	Groups []TaskGroup
+}
 
-	// Write the records to the writer
-	for _, task := range tasks {
-		fmt.Fprintf(w, "%d\t%s\t%s\t%t\n", task.ID, task.Description, timeDiff(task.CreatedAt), task.IsCompleted)
+// All returns the listed tasks without their grouping.
+func (l TaskList) All() []Tasks {
+	var tasks []Tasks
+	for _, group := range l.Groups {
+		tasks = append(tasks, group.Tasks...)
 	}
+	return tasks
 }
 
-func ShowCompletedTasks() {
-	// Read the file
-	tasks, err := ReadFile()
+// ListTasks returns the tasks selected by opts.
+func ListTasks(s Store, opts ListOptions) (TaskList, error) {
+	tasks, err := s.List()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		return
+		return TaskList{}, err
 	}
-
-	// Create new tabwriter
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.Debug)
-	defer w.Flush() // Flush the writer
-
-	// show the header
-	fmt.Fprintln(w, "ID\tDescription\tCreated At")
-
-	// Write the records to the writer
-	for _, task := range tasks {
-		if task.IsCompleted {
-			fmt.Fprintf(w, "%d\t%s\t%s\n", task.ID, task.Description, timeDiff(task.CreatedAt))
-		}
+	now := time.Now()
+	opts, q, err := opts.resolve(now)
+	if err != nil {
+		return TaskList{}, err
 	}
-}
-
-func AddNewTask(description string) {
-	// Read the file
-	tasks, err := ReadFile()
+	tasks, err = opts.apply(tasks, q, now)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		return
+		return TaskList{}, err
 	}
-
-	// Create new task
-	newTask := Tasks{
-		ID:          len(tasks) + 1,
-		Description: description,
-		CreatedAt:   time.Now(),
-		IsCompleted: false,
+	groups, err := groupTasks(tasks, opts.GroupBy)
+	if err != nil {
+		return TaskList{}, err
 	}
-
-	// Write the new task to the file
-	if err := AppendToFile(newTask); err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		return
+	if opts.Tree {
+		for i := range groups {
+			groups[i].Tasks, groups[i].prefix = treeOrder(groups[i].Tasks)
+		}
 	}
 
-	fmt.Fprintln(os.Stdout, "Task added successfully")
+	status := opts.Status
+	if status == "" {
+		status = StatusAll
+	}
+	return TaskList{Status: status, Groups: groups}, nil
 }
 
-func DeleteTask(id int) {
-	task, err := ReadFile()
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		return
+// AddNewTask stores a new task built from the given description and
+// optional fields such as Due, Recur, Parent and BlockedBy. +tag and
+// @project words in the description are moved into Tags and Project.
+func AddNewTask(s Store, newTask Tasks) (Tasks, error) {
+	// Create new task, the store allocates its ID
+	newTask.ID = 0
+	newTask.CreatedAt = time.Now()
+
+	// Pull tags and the project out of the description
+	description, tags, project := ParseDescription(newTask.Description)
+	if description == "" {
+		return Tasks{}, errors.New("task description is empty")
+	}
+	newTask.Description = description
+	given := newTask.Tags
+	newTask.Tags = nil
+	for _, tag := range append(given, tags...) {
+		var err error
+		if newTask.Tags, err = addTag(newTask.Tags, tag); err != nil {
+			return Tasks{}, err
+		}
 	}
-
-	if id > len(task) {
-		fmt.Fprintln(os.Stderr, "Error: Task not found")
-		return
+	if project != "" {
+		newTask.Project = project
 	}
-
-	// Filter the task
-	var newTasks []Tasks
-	for _, t := range task {
-		if t.ID != id {
-			newTasks = append(newTasks, t)
+	if newTask.Recur != "" {
+		r, err := ParseRecurrence(newTask.Recur)
+		if err != nil {
+			return Tasks{}, err
 		}
-	}
-
-	file, err := os.OpenFile("db/db.csv", os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+		newTask.Recur = r.String()
+	}
+
+	var created Tasks
+	err := s.Batch(func(tx Store) error {
+		if newTask.Parent != 0 || len(newTask.BlockedBy) > 0 {
+			tasks, err := tx.List()
+			if err != nil {
+				return err
+			}
+			if err := checkLinks(tasks, newTask); err != nil {
+				return err
+			}
+		}
+		var err error
+		created, err = tx.Create(newTask)
+		return err
+	})
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		return
-	}
-	defer closeFile(file)
-
-	// Lock the file
-	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
+		return Tasks{}, err
 	}
-	defer syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
-
-	// Write the new tasks to the file
-	w := csv.NewWriter(file)
-	defer w.Flush()
-
-	// Write the header
-	w.Write([]string{"ID", "Description", "CreatedAt", "IsCompleted"})
+	return created, nil
+}
 
-	for _, t := range newTasks {
-		record := []string{
-			strconv.Itoa(t.ID),
-			t.Description,
-			t.CreatedAt.Format(time.RFC3339),
-			strconv.FormatBool(t.IsCompleted),
+// DeleteTask removes a task and returns it as it was. Its subtasks move up
+// to its parent and tasks it blocked no longer wait for it.
+func DeleteTask(s Store, id int) (Tasks, error) {
+	var task Tasks
+	err := s.Batch(func(tx Store) error {
+		var err error
+		if task, err = tx.Get(id); err != nil {
+			return err
+		}
+		if err := tx.Delete(id); err != nil {
+			return err
 		}
-		w.Write(record)
+		return unlink(tx, task)
+	})
+	if err != nil {
+		return Tasks{}, err
 	}
+	return task, nil
 }
 
-func CompleteTask(id int) {
-	tasks, err := ReadFile()
+// CompleteTask marks a task as done, keeping the original time if it
+// already was. Completing a recurring task adds its next occurrence. Tasks
+// with open subtasks are refused.
+func CompleteTask(s Store, id int) (Tasks, error) {
+	var task Tasks
+	err := s.Batch(func(tx Store) error {
+		var err error
+		if task, err = tx.Get(id); err != nil {
+			return err
+		}
+		if err := checkSubtasks(tx, []Tasks{task}); err != nil {
+			return err
+		}
+		task, _, err = completeTask(tx, task, time.Now())
+		return err
+	})
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		return
+		return Tasks{}, err
 	}
+	return task, nil
+}
 
-	if id > len(tasks) {
-		fmt.Fprintln(os.Stderr, "Error: Task not found")
-		return
+// completeTask marks task done at now, returning it along with the next
+// occurrence it spawned, if any.
+func completeTask(s Store, task Tasks, now time.Time) (Tasks, *Tasks, error) {
+	if task.Completed() {
+		return task, nil, nil
+	}
+	task.CompletedAt = now
+	task.enter(StateDone, now)
+	task.stopTimer(now)
+	if err := s.Update(task); err != nil {
+		return Tasks{}, nil, err
 	}
 
-	// Mark the task as completed
-	for i, task := range tasks {
-		if task.ID == id {
-			tasks[i].IsCompleted = true
-		}
+	next, ok, err := nextOccurrence(task, now)
+	if err != nil || !ok {
+		return task, nil, err
 	}
+	if next, err = s.Create(next); err != nil {
+		return Tasks{}, nil, err
+	}
+	return task, &next, nil
+}
 
-	file, err := os.OpenFile("db/db.csv", os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+// UncompleteTask reopens a completed task.
+func UncompleteTask(s Store, id int) (Tasks, error) {
+	task, err := s.Get(id)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		return
+		return Tasks{}, err
 	}
-	defer closeFile(file)
 
-	// Lock the file
-	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
+	if task.Completed() {
+		task.CompletedAt = time.Time{}
+		task.enter(StateTodo, time.Now())
 	}
-	defer syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
-
-	// Write the new tasks to the file
-	w := csv.NewWriter(file)
-	defer w.Flush()
-
-	// Write the header
-	w.Write([]string{"ID", "Description", "CreatedAt", "IsCompleted"})
-	for _, t := range tasks {
-		record := []string{
-			strconv.Itoa(t.ID),
-			t.Description,
-			t.CreatedAt.Format(time.RFC3339),
-			strconv.FormatBool(t.IsCompleted),
-		}
-		w.Write(record)
+	if err := s.Update(task); err != nil {
+		return Tasks{}, err
 	}
-
+	return task, nil
 }
@@ -0,0 +1,185 @@
+package tasks_test
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+)
+
+func TestAddNewTask(t *testing.T) {
+	s := tasks.NewMemoryStore()
+
+	task, err := tasks.AddNewTask(s, tasks.Tasks{
+		Description: "Fix login +bug @web",
+		Tags:        []string{"auth"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.ID != 1 || task.Description != "Fix login" || task.Project != "web" {
+		t.Fatalf("unexpected task %+v", task)
+	}
+	if !slices.Equal(task.Tags, []string{"auth", "bug"}) {
+		t.Fatalf("got tags %v", task.Tags)
+	}
+	if task.CreatedAt.IsZero() {
+		t.Fatal("CreatedAt not set")
+	}
+
+	if _, err := tasks.AddNewTask(s, tasks.Tasks{Description: "  +only-tags "}); err == nil {
+		t.Fatal("expected error for empty description")
+	}
+	if _, err := tasks.AddNewTask(s, tasks.Tasks{Description: "Plan", Tags: []string{"two words"}}); err == nil {
+		t.Fatal("expected error for a tag with a space")
+	}
+}
+
+func TestCompleteAndUncompleteTask(t *testing.T) {
+	done := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	s := tasks.NewMemoryStore(
+		tasks.Tasks{ID: 1, Description: "open"},
+		tasks.Tasks{ID: 2, Description: "done", CompletedAt: done},
+	)
+
+	task, err := tasks.CompleteTask(s, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !task.Completed() {
+		t.Fatal("task 1 not completed")
+	}
+
+	// Completing twice keeps the original time
+	task, err = tasks.CompleteTask(s, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !task.CompletedAt.Equal(done) {
+		t.Fatalf("completion time changed to %v", task.CompletedAt)
+	}
+
+	if _, err := tasks.UncompleteTask(s, 2); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := s.Get(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Completed() {
+		t.Fatal("task 2 still completed")
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	s := tasks.NewMemoryStore(tasks.Tasks{ID: 1, Description: "only"})
+
+	for name, call := range map[string]func() error{
+		"complete":   func() error { _, err := tasks.CompleteTask(s, 7); return err },
+		"uncomplete": func() error { _, err := tasks.UncompleteTask(s, 7); return err },
+		"delete":     func() error { _, err := tasks.DeleteTask(s, 7); return err },
+	} {
+		if err := call(); !errors.Is(err, tasks.ErrNotFound) {
+			t.Errorf("%s: got %v, want ErrNotFound", name, err)
+		}
+	}
+}
+
+func TestDeleteTask(t *testing.T) {
+	s := tasks.NewMemoryStore(
+		tasks.Tasks{ID: 1, Description: "keep"},
+		tasks.Tasks{ID: 2, Description: "remove"},
+	)
+
+	task, err := tasks.DeleteTask(s, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Description != "remove" {
+		t.Fatalf("deleted %+v", task)
+	}
+	if _, err := s.Get(2); !errors.Is(err, tasks.ErrNotFound) {
+		t.Fatalf("got %v, want ErrNotFound", err)
+	}
+}
+
+func TestListTasks(t *testing.T) {
+	s := tasks.NewMemoryStore(
+		tasks.Tasks{ID: 1, Description: "a", Project: "web"},
+		tasks.Tasks{ID: 2, Description: "b", CompletedAt: time.Now()},
+		tasks.Tasks{ID: 3, Description: "c", Project: "api"},
+	)
+
+	list, err := tasks.ListTasks(s, tasks.ListOptions{Status: tasks.StatusOpen, GroupBy: tasks.GroupByProject})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, group := range list.Groups {
+		names = append(names, group.Name)
+	}
+	if !slices.Equal(names, []string{"@api", "@web"}) {
+		t.Fatalf("got groups %v", names)
+	}
+	if len(list.All()) != 2 {
+		t.Fatalf("got %d tasks, want 2", len(list.All()))
+	}
+
+	if _, err := tasks.ListTasks(s, tasks.ListOptions{GroupBy: "colour"}); err == nil {
+		t.Fatal("expected error for unknown grouping")
+	}
+}
+
+func TestCSVStoreCorruptRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.csv")
+	if err := os.WriteFile(path, []byte("ID,Description,CreatedAt,CompletedAt\nx,broken,,\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tasks.NewCSVStore(path).List(); !errors.Is(err, tasks.ErrCorruptRecord) {
+		t.Fatalf("got %v, want ErrCorruptRecord", err)
+	}
+}
+
+func TestSQLiteStorePath(t *testing.T) {
+	// Characters with a meaning in URIs are part of the file name
+	path := filepath.Join(t.TempDir(), "what?#100%", "db.sqlite")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	s, err := tasks.NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	mustCreate(t, s, "stored")
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("database not at %s: %v", path, err)
+	}
+}
+
+func TestSQLiteStoreCorruptRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.sqlite")
+	s, err := tasks.NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	mustCreate(t, s, "broken")
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("UPDATE tasks SET due = 'next week'"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.List(); !errors.Is(err, tasks.ErrCorruptRecord) {
+		t.Fatalf("got %v, want ErrCorruptRecord", err)
+	}
+}
@@ -0,0 +1,299 @@
+package tasks
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TimeEntry is a stretch of time spent on a task. A running timer is an
+// entry without an end.
+type TimeEntry struct {
+	Start time.Time
+	End   time.Time // zero while the timer runs
+}
+
+// Running reports whether the entry is a timer still running.
+func (e TimeEntry) Running() bool {
+	return e.End.IsZero()
+}
+
+// duration is the length of the entry, counting a running timer up to now.
+func (e TimeEntry) duration(now time.Time) time.Duration {
+	if e.Running() {
+		return max(now.Sub(e.Start), 0)
+	}
+	return e.End.Sub(e.Start)
+}
+
+// Elapsed is the time spent on the task so far.
+func (t Tasks) Elapsed(now time.Time) time.Duration {
+	var total time.Duration
+	for _, e := range t.TimeLog {
+		total += e.duration(now)
+	}
+	return total
+}
+
+// Tracking reports whether a timer runs on the task.
+func (t Tasks) Tracking() bool {
+	return slices.ContainsFunc(t.TimeLog, TimeEntry.Running)
+}
+
+// stopTimer ends the running timer of the task at now, if it has one.
+func (t *Tasks) stopTimer(now time.Time) bool {
+	stopped := false
+	for i, e := range t.TimeLog {
+		if e.Running() {
+			t.TimeLog[i].End = now
+			stopped = true
+		}
+	}
+	return stopped
+}
+
+// StartTimer starts a timer on a task, stopping the one running on any
+// other task first, as time is tracked on one task at a time. It returns
+// the task and the tasks whose timers were stopped. A task already being
+// timed is left alone; completed tasks are refused.
+func StartTimer(s Store, id int) (Tasks, []Tasks, error) {
+	var (
+		task    Tasks
+		stopped []Tasks
+	)
+	err := s.Batch(func(tx Store) error {
+		var err error
+		if task, err = tx.Get(id); err != nil {
+			return err
+		}
+		if task.Completed() {
+			return fmt.Errorf("task %d is completed, uncomplete it to time it", id)
+		}
+		if task.Tracking() {
+			return nil
+		}
+		now := time.Now()
+		if stopped, err = stopTimers(tx, now); err != nil {
+			return err
+		}
+		task.TimeLog = append(task.TimeLog, TimeEntry{Start: now})
+		return tx.Update(task)
+	})
+	if err != nil {
+		return Tasks{}, nil, err
+	}
+	return task, stopped, nil
+}
+
+// StopTimer stops the running timer, returning the task it ran on and how
+// long it ran. It fails with ErrNoTimer when no timer runs.
+func StopTimer(s Store) (Tasks, time.Duration, error) {
+	now := time.Now()
+	var stopped []Tasks
+	err := s.Batch(func(tx Store) error {
+		var err error
+		stopped, err = stopTimers(tx, now)
+		return err
+	})
+	if err != nil {
+		return Tasks{}, 0, err
+	}
+	if len(stopped) == 0 {
+		return Tasks{}, 0, ErrNoTimer
+	}
+	task := stopped[0]
+	var timed time.Duration
+	for _, e := range task.TimeLog {
+		if e.End.Equal(now) {
+			timed += e.duration(now)
+		}
+	}
+	return task, timed, nil
+}
+
+func stopTimers(s Store, now time.Time) ([]Tasks, error) {
+	tasks, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	var stopped []Tasks
+	for _, task := range tasks {
+		if !task.stopTimer(now) {
+			continue
+		}
+		if err := s.Update(task); err != nil {
+			return nil, err
+		}
+		stopped = append(stopped, task)
+	}
+	return stopped, nil
+}
+
+// LogTime records time spent on a task without a timer. The entry ends now,
+// or when day is set, starts at the beginning of that day.
+func LogTime(s Store, id int, d time.Duration, day time.Time) (Tasks, error) {
+	if d <= 0 {
+		return Tasks{}, errors.New("logged time must be positive")
+	}
+	entry := TimeEntry{Start: time.Now().Add(-d), End: time.Now()}
+	if !day.IsZero() {
+		entry = TimeEntry{Start: startOfDay(day), End: startOfDay(day).Add(d)}
+	}
+
+	var task Tasks
+	err := s.Batch(func(tx Store) error {
+		var err error
+		if task, err = tx.Get(id); err != nil {
+			return err
+		}
+		task.TimeLog = append(task.TimeLog, entry)
+		return tx.Update(task)
+	})
+	if err != nil {
+		return Tasks{}, err
+	}
+	return task, nil
+}
+
+// TimeReport sums up the time spent on tasks between From and To.
+type TimeReport struct {
+	From, To time.Time
+	Total    time.Duration
+	ByTask   []TimeRow
+	ByTag    []TimeRow
+	ByDay    []TimeRow
+}
+
+// TimeRow is the time spent on one task, tag or day of a report.
+type TimeRow struct {
+	Task  *Tasks    // set in rows by task
+	Name  string    // tag, or day as 2006-01-02
+	Day   time.Time // start of the day, in rows by day
+	Spent time.Duration
+}
+
+// ReportTime sums up the time logged on tasks between from and to, cutting
+// entries that cross either end. Tasks are listed with the most time
+// first, tags too, with untagged time under "-", and days in order. Time
+// spent on a task with several tags counts for each of them.
+func ReportTime(tasks []Tasks, from, to time.Time) TimeReport {
+	report := TimeReport{From: from, To: to}
+	byTag := make(map[string]time.Duration)
+	byDay := make(map[time.Time]time.Duration)
+
+	for _, task := range tasks {
+		var spent time.Duration
+		for _, e := range task.TimeLog {
+			start, end := e.Start, e.End
+			if e.Running() {
+				end = to
+			}
+			start, end = later(start, from), earlier(end, to)
+			// Split at midnight so each day gets its share
+			for start.Before(end) {
+				next := earlier(startOfDay(start).AddDate(0, 0, 1), end)
+				byDay[startOfDay(start)] += next.Sub(start)
+				spent += next.Sub(start)
+				start = next
+			}
+		}
+		if spent == 0 {
+			continue
+		}
+		report.Total += spent
+		report.ByTask = append(report.ByTask, TimeRow{Task: &task, Spent: spent})
+		if len(task.Tags) == 0 {
+			byTag["-"] += spent
+		}
+		for _, tag := range task.Tags {
+			byTag[tag] += spent
+		}
+	}
+
+	for tag, spent := range byTag {
+		report.ByTag = append(report.ByTag, TimeRow{Name: tag, Spent: spent})
+	}
+	for day, spent := range byDay {
+		report.ByDay = append(report.ByDay, TimeRow{Name: day.Format("2006-01-02"), Day: day, Spent: spent})
+	}
+	mostFirst := func(a, b TimeRow) int {
+		if c := cmp.Compare(b.Spent, a.Spent); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	}
+	slices.SortFunc(report.ByTask, func(a, b TimeRow) int {
+		if c := cmp.Compare(b.Spent, a.Spent); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Task.ID, b.Task.ID)
+	})
+	slices.SortFunc(report.ByTag, mostFirst)
+	slices.SortFunc(report.ByDay, func(a, b TimeRow) int { return a.Day.Compare(b.Day) })
+	return report
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earlier(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek returns midnight on the Monday of the week holding t.
+func StartOfWeek(t time.Time) time.Time {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return startOfDay(t).AddDate(0, 0, -daysSinceMonday)
+}
+
+// ParseDay reads a day in the past or present: "2026-10-12", "today",
+// "yesterday", a weekday meaning the last one, today included, or "3d"
+// ago. It returns the start of that day.
+func ParseDay(s string, now time.Time) (time.Time, error) {
+	value := strings.ToLower(strings.TrimSpace(s))
+	if day, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return day, nil
+	}
+	switch value {
+	case "today":
+		return startOfDay(now), nil
+	case "yesterday":
+		return startOfDay(now).AddDate(0, 0, -1), nil
+	}
+	if day, ok := parseWeekday(value); ok {
+		back := (int(now.Weekday()) - int(day) + 7) % 7
+		return startOfDay(now).AddDate(0, 0, -back), nil
+	}
+	if d, err := ParseDuration(strings.TrimPrefix(strings.TrimSuffix(value, " ago"), "-")); err == nil {
+		return startOfDay(now.Add(-d)), nil
+	}
+	return time.Time{}, fmt.Errorf("cannot understand day %q", s)
+}
+
+// FormatSpent rounds time spent to the minute, as "1h30m" or "45m".
+func FormatSpent(d time.Duration) string {
+	d = d.Round(time.Minute)
+	switch {
+	case d == 0:
+		return "0m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	}
+	return fmt.Sprintf("%dh%02dm", d/time.Hour, d%time.Hour/time.Minute)
+}
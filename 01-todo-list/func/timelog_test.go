@@ -0,0 +1,142 @@
+package tasks_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+)
+
+func TestTimers(t *testing.T) {
+	now := time.Now()
+	s := tasks.NewMemoryStore(
+		tasks.Tasks{ID: 1, Description: "Write report", CreatedAt: now},
+		tasks.Tasks{ID: 2, Description: "Fix bug", CreatedAt: now},
+	)
+
+	if _, _, err := tasks.StartTimer(s, 1); err != nil {
+		t.Fatal(err)
+	}
+	// Starting another timer stops the first
+	task, stopped, err := tasks.StartTimer(s, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !task.Tracking() || len(stopped) != 1 || stopped[0].ID != 1 || stopped[0].Tracking() {
+		t.Fatalf("started %+v, stopped %+v", task, stopped)
+	}
+
+	task, _, err = tasks.StopTimer(s)
+	if err != nil || task.ID != 2 || task.Tracking() {
+		t.Fatalf("stopped %+v, %v", task, err)
+	}
+	if _, _, err := tasks.StopTimer(s); !errors.Is(err, tasks.ErrNoTimer) {
+		t.Errorf("stop without a timer: got %v, want ErrNoTimer", err)
+	}
+
+	// Completing a task stops its timer
+	tasks.StartTimer(s, 1)
+	if task, _ := tasks.CompleteTask(s, 1); task.Tracking() {
+		t.Error("completed task still timed")
+	}
+	if _, _, err := tasks.StartTimer(s, 1); err == nil {
+		t.Error("timer started on a completed task")
+	}
+
+	task, err = tasks.LogTime(s, 2, 90*time.Minute, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := task.Elapsed(time.Now()); elapsed < 90*time.Minute || elapsed > 91*time.Minute {
+		t.Errorf("elapsed %v after logging 1h30m", elapsed)
+	}
+	if _, err := tasks.LogTime(s, 2, -time.Hour, time.Time{}); err == nil {
+		t.Error("logged negative time")
+	}
+}
+
+func TestReportTime(t *testing.T) {
+	day := time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local) // a Monday
+	at := func(days, hours int) time.Time { return day.AddDate(0, 0, days).Add(time.Duration(hours) * time.Hour) }
+	list := []tasks.Tasks{
+		{ID: 1, Description: "Report", Tags: []string{"work", "writing"}, TimeLog: []tasks.TimeEntry{
+			{Start: at(0, 9), End: at(0, 11)},
+			{Start: at(1, 23), End: at(2, 1)}, // across midnight
+		}},
+		{ID: 2, Description: "Garden", TimeLog: []tasks.TimeEntry{
+			{Start: at(-1, 10), End: at(0, 1)}, // partly before the week
+		}},
+		{ID: 3, Description: "Idle"},
+	}
+
+	if got := tasks.StartOfWeek(at(3, 15)); !got.Equal(day) {
+		t.Errorf("week of Thursday starts %v, want %v", got, day)
+	}
+	report := tasks.ReportTime(list, day, at(7, 0))
+	if report.Total != 5*time.Hour {
+		t.Errorf("total %v, want 5h", report.Total)
+	}
+	if len(report.ByTask) != 2 || report.ByTask[0].Task.ID != 1 || report.ByTask[0].Spent != 4*time.Hour || report.ByTask[1].Spent != time.Hour {
+		t.Errorf("by task: %+v", report.ByTask)
+	}
+	tags := map[string]time.Duration{}
+	for _, row := range report.ByTag {
+		tags[row.Name] = row.Spent
+	}
+	if tags["work"] != 4*time.Hour || tags["writing"] != 4*time.Hour || tags["-"] != time.Hour {
+		t.Errorf("by tag: %v", tags)
+	}
+	var days []string
+	for _, row := range report.ByDay {
+		days = append(days, row.Name+" "+tasks.FormatSpent(row.Spent))
+	}
+	want := []string{"2026-10-12 3h", "2026-10-13 1h", "2026-10-14 1h"}
+	if !slices.Equal(days, want) {
+		t.Errorf("by day: %v, want %v", days, want)
+	}
+}
+
+func TestParseDay(t *testing.T) {
+	now := time.Date(2026, 10, 15, 14, 30, 0, 0, time.Local) // a Thursday
+	tests := map[string]string{
+		"today":      "2026-10-15",
+		"yesterday":  "2026-10-14",
+		"monday":     "2026-10-12",
+		"thursday":   "2026-10-15",
+		"3d":         "2026-10-12",
+		"2026-10-01": "2026-10-01",
+	}
+	for input, want := range tests {
+		got, err := tasks.ParseDay(input, now)
+		if err != nil || got.Format("2006-01-02 15:04") != want+" 00:00" {
+			t.Errorf("ParseDay(%q) = %v, %v; want %s", input, got, err, want)
+		}
+	}
+	if _, err := tasks.ParseDay("someday", now); err == nil {
+		t.Error("parsed someday")
+	}
+}
+
+func TestTimeLogPersists(t *testing.T) {
+	for name, open := range openStores(t) {
+		t.Run(name, func(t *testing.T) {
+			s := open()
+			mustCreate(t, s, "one")
+			tasks.LogTime(s, 1, time.Hour, time.Time{})
+			tasks.StartTimer(s, 1)
+			s.Close()
+
+			s = open()
+			defer s.Close()
+			task, err := s.Get(1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(task.TimeLog) != 2 || !task.Tracking() || task.Elapsed(time.Now()) < time.Hour {
+				t.Errorf("got time log %+v", task.TimeLog)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// The workflow states every workflow starts and ends with. They stand for
+// open and completed tasks, so tasks added or reopened are todo and tasks
+// completed in any way are done.
+const (
+	StateTodo = "todo"
+	StateDone = "done"
+)
+
+// Workflow is the ordered list of states tasks move through, todo first
+// and done last.
+type Workflow []string
+
+// DefaultWorkflow is used until another one is saved.
+var DefaultWorkflow = Workflow{StateTodo, "in-progress", "review", StateDone}
+
+// Transition records a task entering a workflow state.
+type Transition struct {
+	State string
+	At    time.Time
+}
+
+// NewWorkflow builds a workflow from the states between todo and done.
+// State names are lowercase letters, digits and dashes.
+func NewWorkflow(states []string) (Workflow, error) {
+	w := Workflow{StateTodo}
+	for _, state := range states {
+		if err := checkStateName(state); err != nil {
+			return nil, err
+		}
+		if slices.Contains(w, state) || state == StateDone {
+			return nil, fmt.Errorf("state %q given twice", state)
+		}
+		w = append(w, state)
+	}
+	return append(w, StateDone), nil
+}
+
+func checkStateName(state string) error {
+	valid := state != "" && strings.Trim(state, "-") == state
+	for _, r := range state {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+			valid = false
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid state %q (use lowercase letters, digits and dashes)", state)
+	}
+	return nil
+}
+
+// Started is the state tasks enter when work on them starts: the first one
+// after todo.
+func (w Workflow) Started() (string, error) {
+	if len(w) < 3 {
+		return "", errors.New("the workflow has no state between todo and done")
+	}
+	return w[1], nil
+}
+
+// check rejects states that are not part of the workflow.
+func (w Workflow) check(state string) error {
+	if slices.Contains(w, state) {
+		return nil
+	}
+	return fmt.Errorf("unknown state %q (want %s)", state, w)
+}
+
+func (w Workflow) String() string {
+	if len(w) < 2 {
+		return strings.Join(w, ", ")
+	}
+	return strings.Join(w[:len(w)-1], ", ") + " or " + w[len(w)-1]
+}
+
+// workflowPath holds the workflow of a data file, one state between todo
+// and done per line.
+func workflowPath(dataPath string) string {
+	return dataPath + ".workflow"
+}
+
+// LoadWorkflow reads the workflow saved for a data file, DefaultWorkflow
+// if none was.
+func LoadWorkflow(dataPath string) (Workflow, error) {
+	data, err := os.ReadFile(workflowPath(dataPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultWorkflow, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	w, err := NewWorkflow(strings.Fields(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", workflowPath(dataPath), err)
+	}
+	return w, nil
+}
+
+// SaveWorkflow sets the workflow of a data file. Saving nil goes back to
+// DefaultWorkflow.
+func SaveWorkflow(dataPath string, workflow Workflow) error {
+	if workflow == nil {
+		err := os.Remove(workflowPath(dataPath))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	return writeFileAtomic(workflowPath(dataPath), func(w io.Writer) error {
+		for _, state := range workflow[1 : len(workflow)-1] {
+			if _, err := fmt.Fprintln(w, state); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// WorkflowState returns the state the task is in.
+func (t Tasks) WorkflowState() string {
+	switch {
+	case t.Completed():
+		return StateDone
+	case t.State == "":
+		return StateTodo
+	}
+	return t.State
+}
+
+// enter moves the task to state at the given time. Only the states between
+// todo and done are kept in State; those two follow from CompletedAt.
+func (t *Tasks) enter(state string, at time.Time) {
+	t.State = ""
+	if state != StateTodo && state != StateDone {
+		t.State = state
+	}
+	t.Transitions = append(t.Transitions, Transition{State: state, At: at})
+}
+
+// MoveTask moves a task to another state of the workflow. Moving it to done
+// completes it as CompleteTask does, refusing tasks with open subtasks
+// unless force is set, and returns the next occurrence of a recurring task;
+// moving it out of done reopens it. Tasks already in state are returned
+// unchanged.
+func MoveTask(s Store, w Workflow, id int, state string, force bool) (Tasks, *Tasks, error) {
+	if err := w.check(state); err != nil {
+		return Tasks{}, nil, err
+	}
+
+	var (
+		task Tasks
+		next *Tasks
+	)
+	err := s.Batch(func(tx Store) error {
+		var err error
+		if task, err = tx.Get(id); err != nil {
+			return err
+		}
+		if task.WorkflowState() == state {
+			return nil
+		}
+
+		now := time.Now()
+		if state == StateDone {
+			if !force {
+				if err := checkSubtasks(tx, []Tasks{task}); err != nil {
+					return err
+				}
+			}
+			task, next, err = completeTask(tx, task, now)
+			return err
+		}
+		task.CompletedAt = time.Time{}
+		task.enter(state, now)
+		return tx.Update(task)
+	})
+	if err != nil {
+		return Tasks{}, nil, err
+	}
+	return task, next, nil
+}
+
+// Board is the tasks laid out by workflow state, one column per state.
+type Board struct {
+	Columns []BoardColumn
+}
+
+// BoardColumn holds the tasks in one workflow state.
+type BoardColumn struct {
+	State string
+	Tasks []Tasks
+}
+
+// BoardTasks lays out the tasks selected by opts in the states of w, plus
+// any states tasks are left in that w no longer has, before done. Tasks
+// are sorted as opts asks, by priority and due date by default. When
+// doneWithin is positive, tasks completed longer ago are left out.
+func BoardTasks(s Store, w Workflow, opts ListOptions, doneWithin time.Duration) (Board, error) {
+	if opts.Status == "" {
+		opts.Status = StatusAll
+	}
+	if opts.SortBy == "" {
+		opts.SortBy = "-priority,due,id"
+	}
+	opts.GroupBy = ""
+	list, err := ListTasks(s, opts)
+	if err != nil {
+		return Board{}, err
+	}
+
+	states := slices.Clone(w)
+	byState := make(map[string][]Tasks)
+	since := time.Now().Add(-doneWithin)
+	for _, task := range list.All() {
+		if doneWithin > 0 && task.Completed() && task.CompletedAt.Before(since) {
+			continue
+		}
+		state := task.WorkflowState()
+		if !slices.Contains(states, state) {
+			states = slices.Insert(states, len(states)-1, state)
+		}
+		byState[state] = append(byState[state], task)
+	}
+
+	var board Board
+	for _, state := range states {
+		board.Columns = append(board.Columns, BoardColumn{State: state, Tasks: byState[state]})
+	}
+	return board, nil
+}
@@ -0,0 +1,162 @@
+package tasks_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+)
+
+func states(task tasks.Tasks) []string {
+	var states []string
+	for _, transition := range task.Transitions {
+		states = append(states, transition.State)
+	}
+	return states
+}
+
+func TestMoveTask(t *testing.T) {
+	now := time.Now()
+	s := tasks.NewMemoryStore(
+		tasks.Tasks{ID: 1, Description: "Ship feature", CreatedAt: now},
+		tasks.Tasks{ID: 2, Description: "Write tests", CreatedAt: now, Parent: 1},
+	)
+	w := tasks.DefaultWorkflow
+
+	task, _, err := tasks.MoveTask(s, w, 1, "in-progress", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.WorkflowState() != "in-progress" || task.Completed() {
+		t.Fatalf("got state %q", task.WorkflowState())
+	}
+	if _, _, err := tasks.MoveTask(s, w, 1, "shipped", false); err == nil {
+		t.Error("moved to a state outside the workflow")
+	}
+
+	// Done completes, as long as the subtasks are done too
+	if _, _, err := tasks.MoveTask(s, w, 1, tasks.StateDone, false); err == nil {
+		t.Fatal("moved to done with an open subtask")
+	}
+	task, _, err = tasks.MoveTask(s, w, 1, tasks.StateDone, true)
+	if err != nil || !task.Completed() {
+		t.Fatalf("forced move to done: %+v, %v", task, err)
+	}
+
+	// Leaving done reopens; moving to the same state changes nothing
+	task, _, err = tasks.MoveTask(s, w, 1, "review", false)
+	if err != nil || task.Completed() || task.WorkflowState() != "review" {
+		t.Fatalf("move out of done: %+v, %v", task, err)
+	}
+	task, _, _ = tasks.MoveTask(s, w, 1, "review", false)
+	if got, want := states(task), []string{"in-progress", "done", "review"}; !slices.Equal(got, want) {
+		t.Errorf("transitions %v, want %v", got, want)
+	}
+
+	// Completing and reopening outside the workflow is recorded too
+	if _, err := tasks.CompleteTask(s, 2); err != nil {
+		t.Fatal(err)
+	}
+	task, _ = tasks.UncompleteTask(s, 2)
+	if got, want := states(task), []string{"done", "todo"}; !slices.Equal(got, want) {
+		t.Errorf("transitions %v, want %v", got, want)
+	}
+}
+
+func TestNewWorkflow(t *testing.T) {
+	w, err := tasks.NewWorkflow([]string{"doing", "qa"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(w, tasks.Workflow{"todo", "doing", "qa", "done"}) {
+		t.Errorf("got %v", w)
+	}
+	for _, bad := range [][]string{{"Doing"}, {"in progress"}, {"qa", "qa"}, {"done"}, {"-"}} {
+		if _, err := tasks.NewWorkflow(bad); err == nil {
+			t.Errorf("accepted %q", bad)
+		}
+	}
+
+	dataPath := filepath.Join(t.TempDir(), "db.csv")
+	if err := tasks.SaveWorkflow(dataPath, w); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, err := tasks.LoadWorkflow(dataPath); err != nil || !slices.Equal(loaded, w) {
+		t.Errorf("loaded %v, %v", loaded, err)
+	}
+	if err := tasks.SaveWorkflow(dataPath, nil); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, _ := tasks.LoadWorkflow(dataPath); !slices.Equal(loaded, tasks.DefaultWorkflow) {
+		t.Errorf("after reset got %v", loaded)
+	}
+}
+
+func TestBoard(t *testing.T) {
+	now := time.Now()
+	s := tasks.NewMemoryStore(
+		tasks.Tasks{ID: 1, Description: "Plan the quarterly offsite for everyone", CreatedAt: now},
+		tasks.Tasks{ID: 2, Description: "Fix login", CreatedAt: now, State: "in-progress"},
+		tasks.Tasks{ID: 3, Description: "Old work", CreatedAt: now, State: "blocked"},
+		tasks.Tasks{ID: 4, Description: "Shipped", CreatedAt: now, CompletedAt: now.Add(-time.Hour)},
+		tasks.Tasks{ID: 5, Description: "Long gone", CreatedAt: now, CompletedAt: now.AddDate(0, -1, 0)},
+	)
+	board, err := tasks.BoardTasks(s, tasks.DefaultWorkflow, tasks.ListOptions{}, 7*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, column := range board.Columns {
+		var ids []string
+		for _, task := range column.Tasks {
+			ids = append(ids, task.Description)
+		}
+		got = append(got, column.State+": "+strings.Join(ids, ", "))
+	}
+	want := []string{"todo: Plan the quarterly offsite for everyone", "in-progress: Fix login", "review: ", "blocked: Old work", "done: Shipped"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+
+	var out bytes.Buffer
+	p := tasks.Printer{Out: &out, Format: tasks.OutputTable}
+	if err := p.PrintBoard(board, 100); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(out.String(), "\n")
+	if !strings.HasPrefix(lines[0], "TODO (1)") || !strings.Contains(lines[0], "IN-PROGRESS (1)") {
+		t.Errorf("headers: %q", lines[0])
+	}
+	// Five columns of 17 cells, 3 apart
+	if !strings.HasPrefix(lines[2], "#1 Plan the ") || strings.Index(lines[2], "#2 Fix login") != 20 ||
+		strings.Index(lines[2], "#3 Old work") != 60 || lines[3] != "   quarterly" {
+		t.Errorf("cards not wrapped to the columns:\n%s", out.String())
+	}
+}
+
+func TestStatesPersist(t *testing.T) {
+	for name, open := range openStores(t) {
+		t.Run(name, func(t *testing.T) {
+			s := open()
+			mustCreate(t, s, "one")
+			if _, _, err := tasks.MoveTask(s, tasks.DefaultWorkflow, 1, "review", false); err != nil {
+				t.Fatal(err)
+			}
+			s.Close()
+
+			s = open()
+			defer s.Close()
+			task, err := s.Get(1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if task.State != "review" || len(task.Transitions) != 1 || time.Since(task.Transitions[0].At) > time.Minute {
+				t.Errorf("got state %q, transitions %v", task.State, task.Transitions)
+			}
+		})
+	}
+}
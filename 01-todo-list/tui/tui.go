@@ -0,0 +1,409 @@
+// Package tui is the full-screen terminal interface started by tasks and
+// tasks ui. It works on the same store as the commands.
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Changer returns the store a single change is made through, named after
+// the action, so that every change is journaled and undone on its own.
+type Changer func(action string) tasks.Store
+
+// Run shows the interface until the user quits.
+func Run(s tasks.Store, change Changer) error {
+	m, err := newModel(s, change)
+	if err != nil {
+		return err
+	}
+	_, err = tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+type mode int
+
+const (
+	modeList   mode = iota
+	modeAdd         // typing a new task
+	modeEdit        // changing the selected task
+	modeFilter      // typing a query
+	modeDelete      // waiting for a delete to be confirmed
+)
+
+// statuses are cycled through with tab.
+var statuses = []string{tasks.StatusOpen, tasks.StatusAll, tasks.StatusDone}
+
+var (
+	headerStyle   = lipgloss.NewStyle().Bold(true)
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	doneStyle     = lipgloss.NewStyle().Faint(true)
+	overdueStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	helpStyle     = lipgloss.NewStyle().Faint(true)
+)
+
+const help = "↑/↓ move · a add · e edit · space complete · p priority · d delete · / filter · tab status · q quit"
+
+type model struct {
+	store  tasks.Store
+	change Changer
+	opts   tasks.ListOptions
+	items  []tasks.Tasks
+
+	cursor int
+	offset int // first row on screen
+	width  int
+	height int
+
+	mode   mode
+	input  textinput.Model
+	status string // outcome of the last action
+	failed bool   // status is an error
+}
+
+func newModel(s tasks.Store, change Changer) (model, error) {
+	m := model{
+		store:  s,
+		change: change,
+		opts:   tasks.ListOptions{Status: tasks.StatusOpen},
+		input:  textinput.New(),
+	}
+	return m, m.reload()
+}
+
+func (m model) Init() tea.Cmd {
+	return nil
+}
+
+// reload lists the tasks again, keeping the selected task under the cursor
+// when it is still shown.
+func (m *model) reload() error {
+	list, err := tasks.ListTasks(m.store, m.opts)
+	if err != nil {
+		return err
+	}
+	selected := 0
+	if task, ok := m.selected(); ok {
+		selected = task.ID
+	}
+	m.items = list.All()
+	m.cursor = min(m.cursor, max(len(m.items)-1, 0))
+	m.moveTo(selected)
+	return nil
+}
+
+// moveTo puts the cursor on the task with the given ID, if it is shown.
+func (m *model) moveTo(id int) {
+	for i, task := range m.items {
+		if task.ID == id {
+			m.cursor = i
+		}
+	}
+}
+
+func (m model) selected() (tasks.Tasks, bool) {
+	if m.cursor >= len(m.items) {
+		return tasks.Tasks{}, false
+	}
+	return m.items[m.cursor], true
+}
+
+// report shows the outcome of an action and lists the tasks again.
+func (m *model) report(message string, err error) {
+	if err == nil {
+		err = m.reload()
+	}
+	if err != nil {
+		m.status, m.failed = err.Error(), true
+		return
+	}
+	m.status, m.failed = message, false
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.input.Width = max(msg.Width-lipgloss.Width(m.input.Prompt)-1, 1)
+		return m, nil
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		var cmd tea.Cmd
+		switch m.mode {
+		case modeList:
+			m, cmd = m.updateList(msg)
+		case modeDelete:
+			m = m.updateDelete(msg)
+		default:
+			m, cmd = m.updateInput(msg)
+		}
+		m.scroll()
+		return m, cmd
+	}
+
+	if m.mode == modeAdd || m.mode == modeEdit || m.mode == modeFilter {
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m model) updateList(msg tea.KeyMsg) (model, tea.Cmd) {
+	task, ok := m.selected()
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "up", "k":
+		m.cursor = max(m.cursor-1, 0)
+	case "down", "j":
+		m.cursor = min(m.cursor+1, max(len(m.items)-1, 0))
+	case "pgup":
+		m.cursor = max(m.cursor-m.rows(), 0)
+	case "pgdown":
+		m.cursor = min(m.cursor+m.rows(), max(len(m.items)-1, 0))
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		m.cursor = max(len(m.items)-1, 0)
+	case "a":
+		return m, m.prompt(modeAdd, "Add: ", "")
+	case "e":
+		if ok {
+			return m, m.prompt(modeEdit, "Edit: ", editableText(task))
+		}
+	case "/":
+		return m, m.prompt(modeFilter, "Filter: ", m.opts.Query)
+	case "esc":
+		if m.opts.Query != "" {
+			m.opts.Query = ""
+			m.report("Filter cleared", nil)
+		}
+	case "tab":
+		for i, status := range statuses {
+			if status == m.opts.Status {
+				m.opts.Status = statuses[(i+1)%len(statuses)]
+				break
+			}
+		}
+		m.report("", nil)
+	case " ", "x":
+		if !ok {
+			break
+		}
+		if task.Completed() {
+			_, err := tasks.UncompleteTask(m.change("uncomplete"), task.ID)
+			m.report(fmt.Sprintf("Task %d reopened", task.ID), err)
+		} else {
+			_, err := tasks.CompleteTask(m.change("complete"), task.ID)
+			m.report(fmt.Sprintf("Task %d completed", task.ID), err)
+		}
+	case "p":
+		if ok {
+			priority := (task.Priority + 1) % (tasks.PriorityHigh + 1)
+			_, err := tasks.EditTask(m.change("edit"), task.ID, tasks.TaskEdit{Priority: &priority})
+			m.report(fmt.Sprintf("Task %d updated", task.ID), err)
+		}
+	case "d":
+		if ok {
+			m.mode = modeDelete
+			m.status, m.failed = fmt.Sprintf("Delete task %d? (y/n)", task.ID), false
+		}
+	case "r":
+		m.report("", nil)
+	}
+	return m, nil
+}
+
+func (m model) updateDelete(msg tea.KeyMsg) model {
+	m.mode = modeList
+	task, ok := m.selected()
+	if msg.String() != "y" || !ok {
+		m.status = ""
+		return m
+	}
+	_, err := tasks.DeleteTask(m.change("delete"), task.ID)
+	m.report(fmt.Sprintf("Task %d deleted", task.ID), err)
+	return m
+}
+
+// prompt starts reading a line of text for the given mode.
+func (m *model) prompt(mode mode, prompt, value string) tea.Cmd {
+	m.mode = mode
+	m.status = ""
+	m.input.Prompt = prompt
+	m.input.Width = max(m.width-lipgloss.Width(prompt)-1, 1)
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	return m.input.Focus()
+}
+
+func (m model) updateInput(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = modeList
+		m.input.Blur()
+		return m, nil
+	case tea.KeyEnter:
+		mode := m.mode
+		m.mode = modeList
+		m.input.Blur()
+		m.submit(mode, strings.TrimSpace(m.input.Value()))
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// submit acts on the text entered in the given mode.
+func (m *model) submit(mode mode, value string) {
+	switch mode {
+	case modeAdd:
+		if value == "" {
+			return
+		}
+		task, err := tasks.AddNewTask(m.change("add"), tasks.Tasks{Description: value})
+		m.report(fmt.Sprintf("Task %d added successfully", task.ID), err)
+		m.moveTo(task.ID)
+	case modeEdit:
+		task, ok := m.selected()
+		if !ok {
+			return
+		}
+		description, tags, project := tasks.ParseDescription(value)
+		edit := tasks.TaskEdit{
+			Description: &description,
+			Tags:        append([]string{}, tags...),
+			Project:     &project,
+		}
+		_, err := tasks.EditTask(m.change("edit"), task.ID, edit)
+		m.report(fmt.Sprintf("Task %d updated", task.ID), err)
+	case modeFilter:
+		previous := m.opts.Query
+		m.opts.Query = value
+		if err := m.reload(); err != nil {
+			m.opts.Query = previous
+			m.status, m.failed = err.Error(), true
+			return
+		}
+		m.status, m.failed = "", false
+	}
+}
+
+// editableText writes a task's description with its tags and project, as
+// they would be typed to add it.
+func editableText(t tasks.Tasks) string {
+	words := []string{t.Description}
+	for _, tag := range t.Tags {
+		words = append(words, "+"+tag)
+	}
+	if t.Project != "" {
+		words = append(words, "@"+t.Project)
+	}
+	return strings.Join(words, " ")
+}
+
+// scroll keeps the cursor on screen.
+func (m *model) scroll() {
+	rows := m.rows()
+	m.offset = min(m.offset, m.cursor)
+	if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+}
+
+// rows is how many tasks fit on the screen below the header and above the
+// status and help lines.
+func (m model) rows() int {
+	if m.height == 0 {
+		return 20 // before the first window size arrives
+	}
+	return max(m.height-3, 1)
+}
+
+func (m model) View() string {
+	var b strings.Builder
+
+	header := fmt.Sprintf("tasks · %s (%d)", m.opts.Status, len(m.items))
+	if m.opts.Query != "" {
+		header += " · filter: " + m.opts.Query
+	}
+	b.WriteString(headerStyle.Render(header) + "\n")
+
+	for i := m.offset; i < m.offset+m.rows(); i++ {
+		if i < len(m.items) {
+			b.WriteString(m.row(m.items[i], i == m.cursor))
+		}
+		b.WriteString("\n")
+	}
+
+	switch {
+	case m.mode == modeAdd || m.mode == modeEdit || m.mode == modeFilter:
+		b.WriteString(m.input.View())
+	case m.failed:
+		b.WriteString(errorStyle.Render(m.status))
+	default:
+		b.WriteString(m.status)
+	}
+	b.WriteString("\n" + helpStyle.Render(truncate(help, m.width)))
+	return b.String()
+}
+
+// row renders one task: its ID, whether it is done, its priority, its
+// description with tags and project, and its due date on the right.
+func (m model) row(t tasks.Tasks, selected bool) string {
+	check := "[ ]"
+	if t.Completed() {
+		check = "[x]"
+	}
+	priority := " "
+	if t.Priority != tasks.PriorityNone {
+		priority = strings.ToUpper(t.Priority.String()[:1])
+	}
+	due := ""
+	if !t.Due.IsZero() {
+		due = " " + t.Due.Format("Mon Jan 02")
+	}
+
+	left := fmt.Sprintf("%4d %s %s ", t.ID, check, priority)
+	width := m.width
+	if width == 0 {
+		width = 80
+	}
+	text := truncate(editableText(t), max(width-lipgloss.Width(left)-lipgloss.Width(due), 1))
+	padding := max(width-lipgloss.Width(left)-lipgloss.Width(text)-lipgloss.Width(due), 0)
+	line := left + text + strings.Repeat(" ", padding) + due
+
+	switch {
+	case selected:
+		return selectedStyle.Render(line)
+	case t.Completed():
+		return doneStyle.Render(line)
+	case t.Overdue(time.Now()):
+		return overdueStyle.Render(line)
+	}
+	return line
+}
+
+// truncate shortens s to at most width cells, marking the cut with an
+// ellipsis. A width of zero leaves s alone.
+func truncate(s string, width int) string {
+	if width <= 0 || lipgloss.Width(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
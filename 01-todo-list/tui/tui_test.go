@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tasks "github.com/0xirvan/goprojects/01-todo-list/func"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// press sends keys to the model: single runes, or names such as "enter".
+func press(t *testing.T, m model, keys ...string) model {
+	t.Helper()
+	names := map[string]tea.KeyType{
+		"enter": tea.KeyEnter, "esc": tea.KeyEsc, "tab": tea.KeyTab,
+		"up": tea.KeyUp, "down": tea.KeyDown, "space": tea.KeySpace,
+	}
+	for _, key := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		if kind, ok := names[key]; ok {
+			msg = tea.KeyMsg{Type: kind}
+		}
+		next, _ := m.Update(msg)
+		m = next.(model)
+	}
+	return m
+}
+
+func newTestModel(t *testing.T) (model, *tasks.MemoryStore) {
+	t.Helper()
+	now := time.Now()
+	s := tasks.NewMemoryStore(
+		tasks.Tasks{ID: 1, Description: "Write report", CreatedAt: now, Tags: []string{"work"}},
+		tasks.Tasks{ID: 2, Description: "Water plants", CreatedAt: now},
+		tasks.Tasks{ID: 3, Description: "Call mum", CreatedAt: now},
+	)
+	m, err := newModel(s, func(string) tasks.Store { return s })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, s
+}
+
+func ids(m model) []int {
+	var ids []int
+	for _, task := range m.items {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+func TestAddAndComplete(t *testing.T) {
+	m, s := newTestModel(t)
+	m = press(t, m, "a", "Buy milk +shop", "enter")
+	if m.failed {
+		t.Fatal(m.status)
+	}
+	if got := ids(m); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Fatalf("got %v, want [1 2 3 4]", got)
+	}
+	if task, _ := m.selected(); task.ID != 4 || !slices.Equal(task.Tags, []string{"shop"}) {
+		t.Fatalf("cursor on %+v, want the new task tagged shop", task)
+	}
+
+	// Completing hides the task from the open list; tab shows all tasks
+	m = press(t, m, "space")
+	if task, _ := s.Get(4); !task.Completed() {
+		t.Fatal("task 4 not completed")
+	}
+	if got := ids(m); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("open tasks: got %v, want [1 2 3]", got)
+	}
+	m = press(t, m, "tab")
+	if got := ids(m); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Errorf("all tasks: got %v, want [1 2 3 4]", got)
+	}
+}
+
+func TestEditAndDelete(t *testing.T) {
+	m, s := newTestModel(t)
+	m = press(t, m, "e")
+	if m.input.Value() != "Write report +work" {
+		t.Fatalf("edit starts with %q", m.input.Value())
+	}
+	m.input.SetValue("Write quarterly report +q3 @office")
+	m = press(t, m, "enter")
+	task, _ := s.Get(1)
+	if task.Description != "Write quarterly report" || !slices.Equal(task.Tags, []string{"q3"}) || task.Project != "office" {
+		t.Errorf("got %+v", task)
+	}
+
+	m = press(t, m, "down", "d", "n")
+	if _, err := s.Get(2); err != nil {
+		t.Fatal("delete went ahead without confirmation")
+	}
+	m = press(t, m, "d", "y")
+	if _, err := s.Get(2); err == nil {
+		t.Fatal("task 2 not deleted")
+	}
+	if task, _ := m.selected(); task.ID != 3 {
+		t.Errorf("cursor on task %d after delete, want 3", task.ID)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	m, _ := newTestModel(t)
+	m = press(t, m, "/", "tag:work", "enter")
+	if got := ids(m); !slices.Equal(got, []int{1}) {
+		t.Errorf("got %v, want [1]", got)
+	}
+	if !strings.Contains(m.View(), "filter: tag:work") {
+		t.Error("filter not shown in the header")
+	}
+
+	// A bad query keeps the current list
+	m = press(t, m, "/", " due<soon", "enter")
+	if !m.failed || m.opts.Query != "tag:work" {
+		t.Errorf("bad query: failed %v, query %q", m.failed, m.opts.Query)
+	}
+
+	m = press(t, m, "esc")
+	if got := ids(m); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("after clearing: got %v, want [1 2 3]", got)
+	}
+}